@@ -0,0 +1,331 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"library-management-system/internal/render"
+	"library-management-system/patterns/behavioral/state"
+	"library-management-system/patterns/structural/decorator"
+)
+
+//go:generate go run . states diagram --format mermaid --out doc/state-machine.mmd
+//go:generate go run . states diagram --format dot --out doc/state-machine.dot
+//go:generate go run . states export --out config/book_state_machine.json
+
+// defaultPolicyFile is the circulation policy loaded at startup
+const defaultPolicyFile = "config/circulation_policy.json"
+
+// defaultBooksFile holds the library's base books used by the command line
+const defaultBooksFile = "config/books.json"
+
+// defaultMaintenanceFile lists the copies currently out for repair
+const defaultMaintenanceFile = "config/maintenance_list.json"
+
+// defaultStateMachineFile is the built-in state machine definition exported by go generate and checked by states validate
+const defaultStateMachineFile = "config/book_state_machine.json"
+
+// newHistoryFile creates an empty file for the transition history of one demo run
+// Every run starts a new file, so the same scenario is never recorded twice into one history
+func newHistoryFile() (string, error) {
+	file, err := os.CreateTemp("", "circulation_history-*.jsonl")
+	if err != nil {
+		return "", fmt.Errorf("cannot create history file: %w", err)
+	}
+	return file.Name(), file.Close()
+}
+
+// runCommand dispatches the command line sub-commands
+func runCommand(args []string) error {
+	switch {
+	case matchCommand(args, "maintenance", "list"):
+		return runMaintenanceList(args[2:])
+	case matchCommand(args, "policy", "dry-run"):
+		return runPolicyDryRun(args[2:])
+	case matchCommand(args, "states", "diagram"):
+		return runStatesDiagram(args[2:])
+	case matchCommand(args, "states", "export"):
+		return runStatesExport(args[2:])
+	case matchCommand(args, "states", "validate"):
+		return runStatesValidate(args[2:])
+	case matchCommand(args, "history", "state-at"):
+		return runHistoryStateAt(args[2:])
+	case matchCommand(args, "history", "on-loan"):
+		return runHistoryOnLoan(args[2:])
+	}
+	return fmt.Errorf("unknown command: %s", strings.Join(args, " "))
+}
+
+// matchCommand checks if args start with the given command words
+func matchCommand(args []string, words ...string) bool {
+	if len(args) < len(words) {
+		return false
+	}
+	for i, word := range words {
+		if args[i] != word {
+			return false
+		}
+	}
+	return true
+}
+
+// runMaintenanceList prints all copies currently out for repair according to the maintenance file
+func runMaintenanceList(args []string) error {
+	flags := flag.NewFlagSet("maintenance list", flag.ContinueOnError)
+	maintenanceFile := flags.String("file", defaultMaintenanceFile, "maintenance list file")
+	booksFile := flags.String("books", defaultBooksFile, "books file the repaired copies belong to")
+	at := flags.String("at", "", "report time, now if empty")
+	format := flags.String("format", render.FormatText, "output format: "+strings.Join(render.Formats(), ", "))
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	now := time.Now()
+	if *at != "" {
+		instant, err := parseInstant(*at)
+		if err != nil {
+			return err
+		}
+		now = instant
+	}
+	renderer, err := render.ForFormat(*format)
+	if err != nil {
+		return err
+	}
+	repository, err := decorator.LoadBooksFile(*booksFile, nil)
+	if err != nil {
+		return err
+	}
+	maintenance, err := decorator.LoadMaintenanceFile(*maintenanceFile, repository)
+	if err != nil {
+		return err
+	}
+	return maintenance.Display(os.Stdout, renderer, now)
+}
+
+// runStatesDiagram prints the book state machine generated from its definition
+func runStatesDiagram(args []string) error {
+	flags := flag.NewFlagSet("states diagram", flag.ContinueOnError)
+	format := flags.String("format", state.DiagramMermaid, "diagram format: dot or mermaid")
+	definitionFile := flags.String("file", "", "state machine definition file, the built-in machine if empty")
+	out := flags.String("out", "", "write the diagram to this file instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	machine, err := loadMachine(*definitionFile)
+	if err != nil {
+		return err
+	}
+	diagram, err := machine.Definition().RenderDiagram(*format)
+	if err != nil {
+		return err
+	}
+	if *out != "" {
+		return os.WriteFile(*out, []byte(diagram), 0o644)
+	}
+	fmt.Print(diagram)
+	return nil
+}
+
+// runStatesExport prints the built-in state machine definition as JSON
+func runStatesExport(args []string) error {
+	flags := flag.NewFlagSet("states export", flag.ContinueOnError)
+	out := flags.String("out", "", "write the definition to this file instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	data, err := state.FormatDefinition(state.DefaultDefinition())
+	if err != nil {
+		return err
+	}
+	if *out != "" {
+		return os.WriteFile(*out, data, 0o644)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// runStatesValidate checks a state machine definition file
+func runStatesValidate(args []string) error {
+	flags := flag.NewFlagSet("states validate", flag.ContinueOnError)
+	definitionFile := flags.String("file", defaultStateMachineFile, "state machine definition file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	machine, err := loadMachine(*definitionFile)
+	if err != nil {
+		return err
+	}
+	definition := machine.Definition()
+	fmt.Printf("%s is valid: %d states, %d operations, %d transitions, terminal: %s\n",
+		*definitionFile, len(definition.States), len(definition.Operations), len(definition.Transitions),
+		strings.Join(definition.TerminalStates(), ", "))
+	return nil
+}
+
+// loadMachine loads a state machine definition file, or returns the built-in machine for an empty path
+func loadMachine(path string) (*state.Machine, error) {
+	if path == "" {
+		return state.DefaultMachine(), nil
+	}
+	return state.LoadMachineFile(path)
+}
+
+// runHistoryStateAt prints the state a copy was in at a given time
+func runHistoryStateAt(args []string) error {
+	flags := flag.NewFlagSet("history state-at", flag.ContinueOnError)
+	historyFile := flags.String("file", "", "transition history file, e.g. the one written by the demo")
+	at := flags.String("at", "", "point in time, e.g. 2025-02-10 or 2025-02-10 12:00:00")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || *at == "" || *historyFile == "" {
+		return fmt.Errorf("usage: history state-at --file history.jsonl --at <time> <barcode|isbn>")
+	}
+	instant, err := parseInstant(*at)
+	if err != nil {
+		return err
+	}
+	history, err := state.ReadEventFile(*historyFile)
+	if err != nil {
+		return err
+	}
+	bookState, err := state.StateAt(history, flags.Arg(0), instant)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s was %s at %s\n", flags.Arg(0), bookState.GetStateName(), instant.Format(time.DateTime))
+	if loan, ok := bookState.GetLoan(); ok {
+		fmt.Printf("Borrowed by %s since %s, due %s\n",
+			loan.BorrowerID, loan.CheckedOutAt.Format(time.DateTime), loan.DueAt.Format(time.DateTime))
+	}
+	return nil
+}
+
+// runHistoryOnLoan prints every copy that was on loan at a given time
+func runHistoryOnLoan(args []string) error {
+	flags := flag.NewFlagSet("history on-loan", flag.ContinueOnError)
+	historyFile := flags.String("file", "", "transition history file, e.g. the one written by the demo")
+	at := flags.String("at", "", "point in time, e.g. 2025-02-10 or 2025-02-10 12:00:00")
+	format := flags.String("format", render.FormatText, "output format: "+strings.Join(render.Formats(), ", "))
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *at == "" || *historyFile == "" {
+		return fmt.Errorf("usage: history on-loan --file history.jsonl [--format text] --at <time>")
+	}
+	instant, err := parseInstant(*at)
+	if err != nil {
+		return err
+	}
+	renderer, err := render.ForFormat(*format)
+	if err != nil {
+		return err
+	}
+	history, err := state.ReadEventFile(*historyFile)
+	if err != nil {
+		return err
+	}
+	snapshots, err := state.OnLoanAt(history, instant)
+	if err != nil {
+		return err
+	}
+	return renderer.Render(os.Stdout, loanReport(instant, snapshots))
+}
+
+// loanReport lists the copies on loan at an instant
+func loanReport(instant time.Time, snapshots []state.LoanSnapshot) render.Report {
+	report := render.Report{
+		Title:   fmt.Sprintf("On loan at %s: %d item(s)", instant.Format(time.DateTime), len(snapshots)),
+		Columns: []string{"barcode", "title", "isbn", "state", "borrower", "checked_out_at", "due_at"},
+		Line: func(i int, row []string) string {
+			return fmt.Sprintf("%s '%s' (%s): %s, borrowed by %s since %s, due %s",
+				row[0], row[1], row[2], row[3], row[4], row[5], row[6])
+		},
+	}
+	for _, snapshot := range snapshots {
+		report.Rows = append(report.Rows, []string{snapshot.Barcode, snapshot.Title, snapshot.ISBN, snapshot.State,
+			snapshot.Loan.BorrowerID, snapshot.Loan.CheckedOutAt.Format(time.DateTime), snapshot.Loan.DueAt.Format(time.DateTime)})
+	}
+	return report
+}
+
+// parseInstant parses a point in time given as RFC 3339, date and time, or date (end of that day) in UTC
+func parseInstant(value string) (time.Time, error) {
+	if instant, err := time.Parse(time.RFC3339, value); err == nil {
+		return instant, nil
+	}
+	if instant, err := time.Parse(time.DateTime, value); err == nil {
+		return instant, nil
+	}
+	if day, err := time.Parse(time.DateOnly, value); err == nil {
+		return day.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time '%s', use 2006-01-02, 2006-01-02 15:04:05 or RFC 3339", value)
+}
+
+// runPolicyDryRun prints which circulation policies apply to a book
+// The book is looked up in the books file, or described ad hoc with --category, --tag and --collection
+func runPolicyDryRun(args []string) error {
+	flags := flag.NewFlagSet("policy dry-run", flag.ContinueOnError)
+	policyFile := flags.String("file", defaultPolicyFile, "circulation policy file")
+	booksFile := flags.String("books", defaultBooksFile, "books file to look the ISBN up in")
+	category := flags.String("category", "", "category of an ad-hoc book")
+	tags := flags.String("tag", "", "comma-separated tags of an ad-hoc book")
+	collection := flags.String("collection", "", "collection of an ad-hoc book")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	adHoc := *category != "" || *tags != "" || *collection != ""
+	if flags.NArg() > 1 || (!adHoc && flags.NArg() != 1) {
+		return fmt.Errorf("usage: policy dry-run [--file policy.json] [--books books.json] <isbn>\n" +
+			"       policy dry-run [--file policy.json] [--category c] [--tag t1,t2] [--collection c] [isbn]")
+	}
+
+	policy, err := decorator.LoadPolicyFile(*policyFile)
+	if err != nil {
+		return err
+	}
+	var repository *decorator.BookRepository
+	if adHoc {
+		repository = decorator.NewBookRepository(policy)
+		repository.AddBook(&decorator.Book{Title: "Ad-hoc book", ISBN: flags.Arg(0), Category: *category,
+			Collection: *collection, Tags: splitList(*tags), Copies: 1})
+	} else if repository, err = decorator.LoadBooksFile(*booksFile, policy); err != nil {
+		return err
+	}
+
+	book, rules, err := repository.DryRun(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Book: %s\n", book.GetDetails())
+	if len(rules) == 0 {
+		fmt.Println("No policies apply")
+		return nil
+	}
+	fmt.Println("Applied policies:")
+	for i, rule := range rules {
+		types := make([]string, len(rule.Decorators))
+		for j, spec := range rule.Decorators {
+			types[j] = spec.Type
+		}
+		fmt.Printf("  %d. %s -> %s\n", i+1, rule.Name, strings.Join(types, ", "))
+	}
+	decorated, _ := repository.Fetch(book.ISBN)
+	fmt.Printf("Result: %s\n", decorated.GetDetails())
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,14 @@
+// Package money formats amounts kept in minor currency units
+package money
+
+import "fmt"
+
+// Format formats an amount in minor units as major.minor
+func Format(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
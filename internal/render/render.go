@@ -0,0 +1,157 @@
+// Package render writes reports as plain text, an aligned table, JSON or CSV
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format names accepted by ForFormat
+const (
+	FormatText  = "text"
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatCSV   = "csv"
+)
+
+// Report is output independent of its format: a title, named columns and one row per record
+// Line, Empty and Footer are only used by the human readable formats
+type Report struct {
+	Title   string
+	Columns []string
+	Rows    [][]string
+	// Line formats a row for plain text, without it the row is written as "column: value" pairs
+	Line func(i int, row []string) string
+	// Empty is written instead of the rows when there are none
+	Empty  string
+	Footer string
+}
+
+// Renderer writes a report in one format
+type Renderer interface {
+	Render(w io.Writer, report Report) error
+}
+
+// ForFormat returns the renderer for a format name
+func ForFormat(name string) (Renderer, error) {
+	switch name {
+	case FormatText:
+		return Text{}, nil
+	case FormatTable:
+		return Table{}, nil
+	case FormatJSON:
+		return JSON{}, nil
+	case FormatCSV:
+		return CSV{}, nil
+	}
+	return nil, fmt.Errorf("unknown output format '%s' (use %s)", name, strings.Join(Formats(), ", "))
+}
+
+// Formats returns the supported format names
+func Formats() []string {
+	return []string{FormatText, FormatTable, FormatJSON, FormatCSV}
+}
+
+// Text writes the title, one indented line per row and the footer
+type Text struct{}
+
+// Render writes the report as plain text
+func (Text) Render(w io.Writer, report Report) error {
+	var out strings.Builder
+	if report.Title != "" {
+		out.WriteString(report.Title + "\n")
+	}
+	if len(report.Rows) == 0 && report.Empty != "" {
+		out.WriteString("  " + report.Empty + "\n")
+	}
+	for i, row := range report.Rows {
+		line := pairs(report.Columns, row)
+		if report.Line != nil {
+			line = report.Line(i, row)
+		}
+		out.WriteString("  " + line + "\n")
+	}
+	if report.Footer != "" {
+		out.WriteString("  " + report.Footer + "\n")
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// pairs formats a row as "column: value" pairs, skipping empty values
+func pairs(columns, row []string) string {
+	fields := make([]string, 0, len(row))
+	for i, value := range row {
+		if value != "" && i < len(columns) {
+			fields = append(fields, columns[i]+": "+value)
+		}
+	}
+	return strings.Join(fields, ", ")
+}
+
+// Table writes the title and the rows aligned under a header
+type Table struct{}
+
+// Render writes the report as an aligned table
+func (Table) Render(w io.Writer, report Report) error {
+	if report.Title != "" {
+		if _, err := fmt.Fprintln(w, report.Title); err != nil {
+			return err
+		}
+	}
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(report.Columns))
+	for i, column := range report.Columns {
+		header[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(table, strings.Join(header, "\t"))
+	for _, row := range report.Rows {
+		fmt.Fprintln(table, strings.Join(row, "\t"))
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	if report.Footer != "" {
+		_, err := fmt.Fprintln(w, report.Footer)
+		return err
+	}
+	return nil
+}
+
+// JSON writes the rows as an array of objects keyed by column
+type JSON struct{}
+
+// Render writes the report as indented JSON
+func (JSON) Render(w io.Writer, report Report) error {
+	records := make([]map[string]string, len(report.Rows))
+	for i, row := range report.Rows {
+		records[i] = make(map[string]string, len(report.Columns))
+		for j, column := range report.Columns {
+			if j < len(row) {
+				records[i][column] = row[j]
+			}
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// CSV writes a header line and one line per row
+type CSV struct{}
+
+// Render writes the report as CSV
+func (CSV) Render(w io.Writer, report Report) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(report.Columns); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(report.Rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
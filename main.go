@@ -1,8 +1,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
 
+	"library-management-system/internal/money"
+	"library-management-system/internal/render"
 	"library-management-system/patterns/behavioral/state"
 	"library-management-system/patterns/behavioral/strategy"
 	"library-management-system/patterns/creational/builder"
@@ -11,6 +18,14 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	fmt.Println("=== LIBRARY MANAGEMENT SYSTEM - DESIGN PATTERN DEMO ===")
 	fmt.Println()
 
@@ -136,6 +151,124 @@ func demoDecoratorPattern() {
 	fmt.Printf("\nReference only book: %s\n", referenceBook.GetDetails())
 	fmt.Printf("Can borrow: %t\n", referenceBook.CanBorrow())
 	fmt.Printf("Can read in library: %t\n", referenceBook.CanReadInLibrary())
+
+	var desks sync.WaitGroup
+	for desk := 0; desk < 5; desk++ {
+		desks.Add(1)
+		go func() {
+			defer desks.Done()
+			_ = baseBook.Borrow()
+		}()
+	}
+	desks.Wait()
+	fmt.Printf("\nAfter 5 desks borrow at once: available %d, loaned %d (owned %d)\n",
+		baseBook.AvailableCopies(), baseBook.LoanedCopies(), baseBook.Copies)
+	for i := 0; i < 4; i++ {
+		if err := baseBook.Return(); err != nil {
+			fmt.Printf("Error: %s\n", err)
+		}
+	}
+	fmt.Printf("After returns: %s\n", baseBook.GetDetails())
+
+	checkout := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+
+	newRelease := decorator.NewDailyLoanBookDecorator(baseBook, 7, 1)
+	fmt.Printf("\nNew release: %s\n", newRelease.GetDetails())
+	fmt.Printf("Effective loan rules: %s\n", newRelease.GetLoanRules())
+	fmt.Printf("Due date: %s\n", newRelease.GetLoanRules().DueDate(checkout).Format(time.DateTime))
+
+	courseReserve := decorator.NewShortLoanBookDecorator(newRelease, 3, 0)
+	fmt.Printf("\nCourse reserve: %s\n", courseReserve.GetDetails())
+	fmt.Printf("Effective loan rules: %s\n", courseReserve.GetLoanRules())
+	fmt.Printf("Due date: %s\n", courseReserve.GetLoanRules().DueDate(checkout).Format(time.DateTime))
+
+	maintenance := decorator.NewMaintenanceList()
+	repairBook, err := maintenance.SendForRepair(baseBook, "loose pages", "City Bindery",
+		checkout, checkout.AddDate(0, 0, 14))
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return
+	}
+	fmt.Printf("\nUnder repair: %s\n", repairBook.GetDetails())
+	fmt.Printf("Can borrow: %t\n", repairBook.CanBorrow())
+	fmt.Printf("Can read in library: %t\n", repairBook.CanReadInLibrary())
+	_ = maintenance.Display(os.Stdout, render.Text{}, checkout.AddDate(0, 0, 3))
+
+	ebook := &decorator.Book{
+		Title:  "Go Programming Language",
+		Author: "Alan Donovan",
+		ISBN:   "9780134190440",
+		Copies: 2,
+	}
+	license := decorator.NewDigitalLicenseBookDecorator(ebook, 2, 5, checkout.AddDate(0, 0, 30))
+	license.SetClock(func() time.Time { return checkout })
+	for i := 0; i < 3; i++ {
+		if err := license.Borrow(); err != nil {
+			fmt.Printf("Error: %s\n", err)
+		}
+	}
+	fmt.Printf("\nDigital license: %s\n", license.GetDetails())
+	fmt.Printf("Can borrow: %t\n", license.CanBorrow())
+	if err := license.Return(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	fmt.Printf("Can borrow after return: %t\n", license.CanBorrow())
+	_ = decorator.DisplayLicenseReport(os.Stdout, render.Text{}, []*decorator.DigitalLicenseBookDecorator{license},
+		decorator.LicenseThreshold{RemainingCheckouts: 3, ExpiresWithin: 7 * 24 * time.Hour})
+
+	policy, err := decorator.LoadPolicyFile(defaultPolicyFile)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return
+	}
+	repository, err := decorator.LoadBooksFile(defaultBooksFile, policy)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return
+	}
+	for _, isbn := range []string{"9780198611868", "9781285740621"} {
+		book, _ := repository.Fetch(isbn)
+		fmt.Printf("\nFetched with policy: %s\n", book.GetDetails())
+		fmt.Printf("Can borrow: %t, loan rules: %s\n", book.CanBorrow(), book.GetLoanRules())
+	}
+
+	account := decorator.NewPatronAccount("student-123")
+	dvd, _ := repository.FetchFor("0883929121625", account)
+	fmt.Printf("\nFetched with policy: %s\n", dvd.GetDetails())
+	if err := dvd.Borrow(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	for _, charge := range account.GetCharges() {
+		fmt.Printf("Charged %s: %s\n", charge.Description, money.Format(charge.Amount))
+	}
+	fmt.Printf("Account balance for %s: %s\n", account.PatronID, money.Format(account.Balance()))
+
+	metrics := decorator.NewMetrics()
+	logger := demoLogger()
+	popular, _ := repository.Fetch("9781649374042")
+	instrumented := decorator.NewInstrumentedBookDecorator(popular, metrics, logger)
+	fmt.Println()
+	for i := 0; i < 6; i++ {
+		if instrumented.CanBorrow() {
+			_ = instrumented.Borrow()
+		}
+	}
+	_ = instrumented.Borrow()
+	_ = instrumented.Return()
+	stats := metrics.Get("9781649374042", decorator.OperationBorrow)
+	fmt.Printf("Borrow calls: %d, refused: %d\n", stats.Calls, stats.Failures)
+}
+
+// demoLogger returns a text logger on stdout without timestamps and latencies, so demo output is stable
+func demoLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey || attr.Key == "latency" {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
 }
 
 // STATE PATTERN DEMO
@@ -143,16 +276,22 @@ func demoStatePattern() {
 	fmt.Println("=== STATE PATTERN ===")
 	fmt.Println("Managing book states using State pattern")
 
+	// Books log transitions to slog.Default() unless given their own logger with SetLogger
+	slog.SetDefault(demoLogger())
+	text := render.Text{}
+	clock := state.NewManualClock(time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC))
+
 	book := state.NewBook("The Alchemist", "9780062315007")
-	book.Display()
+	book.SetClock(clock)
+	_ = book.Display(os.Stdout, text)
 
-	err := book.Borrow()
+	err := book.Borrow("student-123")
 	if err != nil {
 		fmt.Printf("Error: %s\n", err)
 	}
-	book.Display()
+	_ = book.Display(os.Stdout, text)
 
-	err = book.Borrow()
+	err = book.Borrow("student-456")
 	if err != nil {
 		fmt.Printf("Error: %s\n", err)
 	}
@@ -161,9 +300,9 @@ func demoStatePattern() {
 	if err != nil {
 		fmt.Printf("Error: %s\n", err)
 	}
-	book.Display()
+	_ = book.Display(os.Stdout, text)
 
-	err = book.Borrow()
+	err = book.Borrow("student-456")
 	if err != nil {
 		fmt.Printf("Error: %s\n", err)
 	}
@@ -172,12 +311,342 @@ func demoStatePattern() {
 	if err != nil {
 		fmt.Printf("Error: %s\n", err)
 	}
-	book.Display()
+	_ = book.Display(os.Stdout, text)
 
 	err = book.Return()
 	if err != nil {
 		fmt.Printf("Error: %s\n", err)
 	}
+
+	fines := state.NewFineLedger()
+	finePolicy := state.FinePolicy{
+		DailyRate:     50,
+		GraceDays:     1,
+		MaxFine:       2000,
+		MaterialRates: map[string]int64{"dvd": 200},
+	}
+
+	shortLoan := state.NewBook("Sapiens", "9780062316097")
+	shortLoan.SetClock(clock)
+	shortLoan.SetLoanPeriod(7 * 24 * time.Hour)
+	shortLoan.SetFinePolicy(finePolicy)
+	shortLoan.SetFineLedger(fines)
+	longLoan := state.NewBook("Atomic Habits", "9780735211292")
+	longLoan.SetClock(clock)
+	longLoan.SetFinePolicy(finePolicy)
+	longLoan.SetFineLedger(fines)
+	longLoan.SetRenewalPolicy(state.RenewalPolicy{MaxRenewals: 2, Period: 7 * 24 * time.Hour, FromDueDate: true})
+	_ = shortLoan.Borrow("student-123")
+	_ = longLoan.Borrow("student-456")
+
+	clock.Advance(10 * 24 * time.Hour)
+	fmt.Printf("\nOverdue sweep at %s:\n", clock.Now().Format(time.DateTime))
+	for _, result := range state.SweepOverdue(clock, []*state.Book{book, shortLoan, longLoan}) {
+		fmt.Printf("  Marked overdue: %s\n", result)
+	}
+	_ = shortLoan.Display(os.Stdout, text)
+	_ = longLoan.Display(os.Stdout, text)
+
+	if err := shortLoan.Return(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	fmt.Printf("Outstanding fines for student-123: %s\n", money.Format(fines.Outstanding("student-123")))
+
+	for i := 0; i < 3; i++ {
+		dueAt, err := longLoan.Renew()
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			continue
+		}
+		fmt.Printf("New due date: %s\n", dueAt.Format(time.DateTime))
+	}
+
+	fmt.Println()
+	history := state.NewMemoryEventStore()
+	holdBook := state.NewBook("Dune", "9780441172719")
+	holdBook.SetClock(clock)
+	if err := holdBook.SetEventStore(history); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	holdBook.SetOperator("desk-1")
+
+	bus := holdBook.GetEventBus()
+	bus.OnEnter(state.StateOnHoldShelf, func(event state.Event) error {
+		fmt.Printf("  [notify] %s: your hold is ready for pickup\n", event.Hold)
+		return nil
+	})
+	bus.Subscribe(func(event state.Event) error {
+		panic("statistics service unavailable")
+	})
+	stats := bus.SubscribeChannel(16)
+	_ = holdBook.Borrow("student-123")
+	_ = holdBook.PlaceHold("student-456")
+	_ = holdBook.Display(os.Stdout, text)
+	if _, err := holdBook.Renew(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	_ = holdBook.Return()
+	if err := holdBook.Borrow("student-789"); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	_ = holdBook.Ship("North Branch")
+	_ = holdBook.Receive()
+	_ = holdBook.Borrow("student-456")
+	_ = holdBook.DeclareLost()
+	_ = holdBook.Withdraw()
+	_ = holdBook.Display(os.Stdout, text)
+	if err := holdBook.Borrow("student-123"); errors.Is(err, state.ErrInvalidTransition) {
+		var transitionErr *state.TransitionError
+		errors.As(err, &transitionErr)
+		fmt.Printf("Error: %s (state %s, operation %s)\n", err, transitionErr.State, transitionErr.Operation)
+	}
+
+	bus.Close()
+	transitions := 0
+	for range stats {
+		transitions++
+	}
+	fmt.Printf("Statistics received %d transitions, %d subscriber failure(s) ignored\n",
+		transitions, len(bus.Failures()))
+
+	fmt.Printf("\nHistory of %s:\n", holdBook.GetTitle())
+	for _, event := range holdBook.GetHistory() {
+		fmt.Printf("  %s\n", event)
+	}
+	fmt.Println("History of student-456:")
+	for _, event := range history.ForPatron("student-456") {
+		fmt.Printf("  %s\n", event)
+	}
+	replayed, err := state.Replay(holdBook.GetTitle(), holdBook.GetISBN(), holdBook.GetHistory())
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return
+	}
+	fmt.Printf("Replayed state: %s\n", replayed.GetStateName())
+
+	contested := state.NewBook("Atomic Habits", "9780735211292")
+	contested.SetClock(clock)
+	var loans sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for desk := 1; desk <= 50; desk++ {
+		loans.Add(1)
+		go func(desk int) {
+			defer loans.Done()
+			if err := contested.Borrow(fmt.Sprintf("desk-%d", desk)); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(desk)
+	}
+	loans.Wait()
+	loan, _ := contested.GetLoan()
+	fmt.Printf("\n50 desks borrow %s at once: %d loan(s), borrower %s, version %d\n",
+		contested.GetTitle(), succeeded, loan.BorrowerID, contested.Version())
+
+	seen := contested.Version()
+	for _, desk := range []string{"desk-A", "desk-B"} {
+		if err := contested.AtVersion(seen).Return(); errors.Is(err, state.ErrConflict) {
+			fmt.Printf("%s: %s\n", desk, err)
+		}
+	}
+	fmt.Printf("State after both desks returned version %d: %s (version %d)\n",
+		seen, contested.GetStateName(), contested.Version())
+
+	patrons := state.NewPatronRegistry()
+	patrons.SetFineLedger(fines)
+	patrons.Register(state.Patron{ID: "student-123", Name: "Alice", Category: "student", Active: true,
+		CardExpiresAt: clock.Now().AddDate(1, 0, 0)})
+	patrons.Register(state.Patron{ID: "student-789", Name: "Dewi", Category: "student", Active: true,
+		CardExpiresAt: clock.Now().AddDate(1, 0, 0), LoansOut: 3, FinesOwed: 750})
+	patrons.Register(state.Patron{ID: "staff-7", Name: "Budi", Category: "staff", Active: true,
+		CardExpiresAt: clock.Now().AddDate(2, 0, 0)})
+	patrons.Register(state.Patron{ID: "visitor-9", Name: "Citra", Category: "visitor", Active: false,
+		CardExpiresAt: clock.Now().AddDate(0, -1, 0)})
+	guarded := state.NewBook("Clean Code", "9780132350884")
+	guarded.SetClock(clock)
+	guarded.SetEligibility(patrons, state.DefaultEligibilityPolicy())
+
+	fmt.Println()
+	for _, patronID := range []string{"visitor-9", "student-789", "unknown-1", "staff-7"} {
+		err := guarded.Borrow(patronID)
+		var eligibilityErr *state.EligibilityError
+		if errors.As(err, &eligibilityErr) {
+			fmt.Printf("%s refused:\n", patronID)
+			for _, reason := range eligibilityErr.Reasons {
+				fmt.Printf("  - %s\n", reason)
+			}
+		}
+	}
+	staff, _ := patrons.FindPatron("staff-7")
+	fmt.Printf("%s now has %d loan(s) out\n", staff.Name, staff.LoansOut)
+
+	alchemist := state.NewTitle("The Alchemist", "9780062315007")
+	for i := 1; i <= 5; i++ {
+		item, err := alchemist.AddCopy(fmt.Sprintf("ALC-%03d", i))
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		item.SetClock(clock)
+		item.SetFineLedger(fines)
+		item.SetReplacementCost(1800)
+	}
+	fmt.Printf("\n%s\n", alchemist.Summary())
+	first, _ := alchemist.Borrow("student-123")
+	clock.Advance(24 * time.Hour)
+	if _, err := alchemist.Borrow("student-456"); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	clock.Advance(15 * 24 * time.Hour)
+	if err := first.MarkOverdue(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	fmt.Println(alchemist.Summary())
+
+	clock.Advance(60 * 24 * time.Hour)
+	fmt.Printf("\nLost item sweep at %s:\n", clock.Now().Format(time.DateTime))
+	for _, result := range state.SweepLost(clock, alchemist.Copies()) {
+		fmt.Printf("  Declared lost: %s\n", result)
+	}
+	fmt.Printf("Outstanding fines for student-123: %s\n", money.Format(fines.Outstanding("student-123")))
+	if err := first.Return(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	fmt.Printf("Outstanding fines for student-123: %s\n", money.Format(fines.Outstanding("student-123")))
+	fmt.Println(alchemist.Summary())
+
+	calendar := state.NewCalendar("Pusat", time.UTC)
+	if err := calendar.LoadICalendarFile("config/library_calendar.ics"); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return
+	}
+	calendarClock := state.NewManualClock(time.Date(2025, time.January, 13, 10, 0, 0, 0, time.UTC))
+	historyFile, err := newHistoryFile()
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return
+	}
+	archive, err := state.OpenFileEventStore(historyFile)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return
+	}
+	scheduled := state.NewBook("Laskar Pelangi", "9789793062792")
+	scheduled.SetClock(calendarClock)
+	if err := scheduled.SetEventStore(archive); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	scheduled.SetCalendar(calendar)
+	scheduled.SetFineLedger(fines)
+	fmt.Println()
+	if err := scheduled.Borrow("student-456"); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	scheduledLoan, _ := scheduled.GetLoan()
+	holiday, _ := calendar.Holiday(calendarClock.Now().Add(state.DefaultLoanPeriod))
+	fmt.Printf("Due date rolled past %s to %s\n", holiday, scheduledLoan.DueAt.Format(time.DateTime))
+	calendarClock.Set(time.Date(2025, time.February, 6, 10, 0, 0, 0, time.UTC))
+	if err := scheduled.MarkOverdue(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	if err := scheduled.Return(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	fmt.Printf("%d day(s) after the due date, %d of them closed\n",
+		state.DaysLate(scheduledLoan.DueAt, calendarClock.Now()),
+		calendar.ClosedDaysBetween(scheduledLoan.DueAt, calendarClock.Now()))
+
+	recalled := state.NewBook("Thinking, Fast and Slow", "9780374533557")
+	recalled.SetClock(calendarClock)
+	if err := recalled.SetEventStore(archive); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	recalled.SetFineLedger(fines)
+	recalled.GetEventBus().Subscribe(state.RecallNotices(state.NotifierFunc(func(notice state.Notice) error {
+		fmt.Printf("Notice to %s: %s\n", notice.PatronID, notice.Message)
+		return nil
+	})))
+	fmt.Println()
+	if err := recalled.Borrow("student-123"); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	calendarClock.Advance(2 * 24 * time.Hour)
+	if err := recalled.Recall("faculty-42"); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	if _, err := recalled.Renew(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	_ = recalled.Display(os.Stdout, text)
+	calendarClock.Advance(9 * 24 * time.Hour)
+	if err := recalled.MarkOverdue(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	if err := recalled.Return(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+
+	claims := state.NewClaimLedger()
+	claims.SetRepeatThreshold(2)
+	fmt.Println()
+	for i, found := range []bool{true, false} {
+		disputed := state.NewItem("Bumi Manusia", "9789799731234", fmt.Sprintf("BM-%03d", i+1))
+		disputed.SetClock(calendarClock)
+		if err := disputed.SetEventStore(archive); err != nil {
+			fmt.Printf("Error: %s\n", err)
+		}
+		disputed.SetFineLedger(fines)
+		disputed.SetClaimLedger(claims)
+		if err := disputed.Borrow("student-456"); err != nil {
+			fmt.Printf("Error: %s\n", err)
+		}
+		calendarClock.Advance(20 * 24 * time.Hour)
+		if err := disputed.MarkOverdue(); err != nil {
+			fmt.Printf("Error: %s\n", err)
+		}
+		if err := disputed.ClaimReturned(); err != nil {
+			fmt.Printf("Error: %s\n", err)
+		}
+		fmt.Printf("Open shelf searches: %d\n", len(claims.OpenTasks()))
+		if err := disputed.ResolveClaim(found); err != nil {
+			fmt.Printf("Error: %s\n", err)
+		}
+	}
+	fmt.Printf("Repeat claimants: %v\n", claims.Flagged())
+
+	if err := archive.Close(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	persisted, err := state.ReadEventFile(historyFile)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return
+	}
+	fmt.Printf("\n%d transitions persisted to %s\n", len(persisted.All()), historyFile)
+	auditAt := time.Date(2025, time.February, 10, 12, 0, 0, 0, time.UTC)
+	if past, err := state.StateAt(persisted, recalled.GetISBN(), auditAt); err == nil {
+		fmt.Printf("%s was %s at %s\n", recalled.GetTitle(), past.GetStateName(), auditAt.Format(time.DateTime))
+	}
+	if _, err := state.StateAt(persisted, "9789799731234", auditAt); err != nil {
+		fmt.Printf("Error: %s\n", err)
+	}
+	disputeAt := time.Date(2025, time.March, 20, 12, 0, 0, 0, time.UTC)
+	for _, barcode := range []string{"BM-001", "BM-002"} {
+		if past, err := state.StateAt(persisted, barcode, disputeAt); err == nil {
+			fmt.Printf("%s was %s at %s\n", barcode, past.GetStateName(), disputeAt.Format(time.DateTime))
+		}
+	}
+	snapshots, err := state.OnLoanAt(persisted, auditAt)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return
+	}
+	fmt.Printf("On loan at %s:\n", auditAt.Format(time.DateTime))
+	for _, snapshot := range snapshots {
+		fmt.Printf("  %s\n", snapshot)
+	}
 }
 
 // STRATEGY PATTERN DEMO
@@ -194,17 +663,47 @@ func demoStrategyPattern() {
 	}
 
 	catalog := strategy.NewCatalog(books)
-	catalog.DisplayCatalog()
+	text := render.Text{}
+	_ = catalog.DisplayCatalog(os.Stdout, text)
 
 	catalog.SetStrategy(strategy.NewTitleSearchStrategy())
 	results := catalog.Find("Clean")
-	catalog.DisplayResults(results, "Clean")
+	fmt.Println()
+	_ = catalog.DisplayResults(os.Stdout, text, results, "Clean")
 
 	catalog.SetStrategy(strategy.NewAuthorSearchStrategy())
 	results = catalog.Find("Robert")
-	catalog.DisplayResults(results, "Robert")
+	fmt.Println()
+	_ = catalog.DisplayResults(os.Stdout, text, results, "Robert")
 
 	catalog.SetStrategy(strategy.NewTitleSearchStrategy())
 	results = catalog.Find("Design")
-	catalog.DisplayResults(results, "Design")
+	fmt.Println()
+	_ = catalog.DisplayResults(os.Stdout, text, results, "Design")
+
+	staffOnly := decorator.NewSuppressedBookDecorator(&decorator.Book{
+		Title:  "Clean Code Instructor Solutions",
+		Author: "Robert C. Martin",
+		ISBN:   "9780132350891",
+	}, "staff only")
+	catalog.AddBook(decorator.CatalogRecord(staffOnly, "Technology"))
+
+	fmt.Printf("\n%s search:", catalog.GetMode())
+	fmt.Println()
+	_ = catalog.DisplayResults(os.Stdout, text, catalog.Find("Clean"), "Clean")
+
+	catalog.SetMode(strategy.StaffSearch)
+	fmt.Printf("\n%s search:", catalog.GetMode())
+	fmt.Println()
+	_ = catalog.DisplayResults(os.Stdout, text, catalog.Find("Clean"), "Clean")
+
+	for _, format := range []string{render.FormatTable, render.FormatJSON, render.FormatCSV} {
+		renderer, err := render.ForFormat(format)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			continue
+		}
+		fmt.Printf("\n%s search as %s:\n", catalog.GetMode(), format)
+		_ = catalog.DisplayResults(os.Stdout, renderer, catalog.Find("Clean"), "Clean")
+	}
 }
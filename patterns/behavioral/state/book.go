@@ -1,20 +1,73 @@
 package state
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"library-management-system/internal/render"
+)
 
 // Book is the context that maintains current state
+// Operations are atomic: each one runs under a per-book lock and bumps the version on every transition
 type Book struct {
-	title string
-	isbn  string
-	state BookState
+	mu              sync.Mutex
+	version         uint64
+	pending         []Event
+	title           string
+	isbn            string
+	barcode         string
+	state           BookState
+	machine         *Machine
+	clock           Clock
+	loanPeriod      time.Duration
+	materialType    string
+	finePolicy      FinePolicy
+	fineLedger      *FineLedger
+	claims          *ClaimLedger
+	renewalPolicy   RenewalPolicy
+	lostPolicy      LostItemPolicy
+	recallPolicy    RecallPolicy
+	calendar        *Calendar
+	replacementCost int64
+	hold            string
+	history         EventStore
+	events          *EventBus
+	operator        string
+	patrons         PatronDirectory
+	eligibility     *EligibilityPolicy
+	logger          *slog.Logger
 }
 
-// NewBook creates a new book with available state
+// NewBook creates a new single-copy book with available state, its barcode is the ISBN
 func NewBook(title, isbn string) *Book {
+	return NewItem(title, isbn, isbn)
+}
+
+// NewItem creates a new copy of a title with available state
+func NewItem(title, isbn, barcode string) *Book {
 	return &Book{
-		title: title,
-		isbn:  isbn,
-		state: NewAvailableState(),
+		title:         title,
+		isbn:          isbn,
+		barcode:       barcode,
+		state:         NewState(DefaultMachine().Definition().Initial),
+		machine:       DefaultMachine(),
+		clock:         SystemClock{},
+		loanPeriod:    DefaultLoanPeriod,
+		materialType:  "book",
+		finePolicy:    DefaultFinePolicy(),
+		fineLedger:    NewFineLedger(),
+		claims:        NewClaimLedger(),
+		renewalPolicy: DefaultRenewalPolicy(),
+		lostPolicy:    DefaultLostItemPolicy(),
+		recallPolicy:  DefaultRecallPolicy(),
+		history:       NewMemoryEventStore(),
+		events:        NewEventBus(),
+		operator:      "system",
+		logger:        slog.Default(),
 	}
 }
 
@@ -28,38 +81,481 @@ func (b *Book) GetISBN() string {
 	return b.isbn
 }
 
-// SetState changes the current state
+// GetBarcode returns the barcode of the copy
+func (b *Book) GetBarcode() string {
+	return b.barcode
+}
+
+// SetClock replaces the clock used for checkout times and due dates
+func (b *Book) SetClock(clock Clock) {
+	b.clock = clock
+}
+
+// SetLoanPeriod changes how long the book is lent
+func (b *Book) SetLoanPeriod(period time.Duration) {
+	b.loanPeriod = period
+}
+
+// SetMaterialType changes the material type used to pick the fine rate
+func (b *Book) SetMaterialType(materialType string) {
+	b.materialType = materialType
+}
+
+// SetFinePolicy changes how overdue fines are calculated
+func (b *Book) SetFinePolicy(policy FinePolicy) {
+	b.finePolicy = policy
+}
+
+// SetFineLedger changes where overdue fines are recorded
+func (b *Book) SetFineLedger(ledger *FineLedger) {
+	b.fineLedger = ledger
+}
+
+// SetClaimLedger changes where claims returned and their shelf searches are recorded
+// Share one ledger between books to count claims per patron across the collection
+func (b *Book) SetClaimLedger(ledger *ClaimLedger) {
+	b.claims = ledger
+}
+
+// SetRenewalPolicy changes how loans of the book can be renewed
+func (b *Book) SetRenewalPolicy(policy RenewalPolicy) {
+	b.renewalPolicy = policy
+}
+
+// SetCalendar makes due dates roll over closed days and fines skip them
+func (b *Book) SetCalendar(calendar *Calendar) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calendar = calendar
+}
+
+// dueDate moves a due date off the days the branch is closed
+func (b *Book) dueDate(dueAt time.Time) time.Time {
+	if b.calendar == nil {
+		return dueAt
+	}
+	return b.calendar.RollDueDate(dueAt)
+}
+
+// daysLate returns the chargeable days late, without closed days when a calendar is set
+func (b *Book) daysLate(dueAt, returnedAt time.Time) int {
+	if b.calendar == nil {
+		return DaysLate(dueAt, returnedAt)
+	}
+	return b.calendar.DaysLate(dueAt, returnedAt)
+}
+
+// SetRecallPolicy changes how recalls shorten loans of the book and how late recalled loans are fined
+func (b *Book) SetRecallPolicy(policy RecallPolicy) {
+	b.recallPolicy = policy
+}
+
+// SetLostItemPolicy changes when the book is declared lost and what the borrower is billed
+func (b *Book) SetLostItemPolicy(policy LostItemPolicy) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lostPolicy = policy
+}
+
+// lostDue returns the overdue loan and the version it was read at when the loan has been late
+// long enough at now for the lost item policy, counting only open days when a calendar is set
+func (b *Book) lostDue(now time.Time) (Loan, uint64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state.GetStateName() != StateOverdue {
+		return Loan{}, 0, false
+	}
+	loan, _ := b.state.GetLoan()
+	return loan, b.version, b.daysLate(loan.DueAt, now) >= b.lostPolicy.DaysOverdue
+}
+
+// SetReplacementCost sets the replacement cost billed when the book is lost
+func (b *Book) SetReplacementCost(cost int64) {
+	b.replacementCost = cost
+}
+
+// SetEventStore changes where the transition history is recorded
+// Share one store between books to query history per patron; a copy with events already
+// in the store resumes from the state its last event moved it to
+func (b *Book) SetEventStore(store EventStore) error {
+	events := store.ForItem(b.barcode)
+	if err := checkChain(events); err != nil {
+		return fmt.Errorf("cannot resume '%s' from its history: %w", b.barcode, err)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(events) > 0 {
+		last := events[len(events)-1]
+		state, err := stateFromEvent(b, last)
+		if err != nil {
+			return fmt.Errorf("cannot resume '%s' from its history: %w", b.barcode, err)
+		}
+		b.state = state
+		b.hold = last.Hold
+		b.version = uint64(len(events))
+	}
+	b.history = store
+	return nil
+}
+
+// SetLogger changes the structured logger transitions and their effects are logged to
+// A nil logger falls back to slog.Default()
+func (b *Book) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.logger = logger
+}
+
+// log writes an info record about the book, must be called with the book lock held
+func (b *Book) log(message string, args ...any) {
+	b.logger.Info(message, append([]any{"title", b.title, "barcode", b.barcode}, args...)...)
+}
+
+// SetEventBus changes the bus transitions are published on
+// Share one bus between books to observe all of them
+func (b *Book) SetEventBus(bus *EventBus) {
+	b.events = bus
+}
+
+// GetEventBus returns the bus transitions are published on
+func (b *Book) GetEventBus() *EventBus {
+	return b.events
+}
+
+// SetOperator sets the staff member or system recorded as actor of the next transitions
+func (b *Book) SetOperator(operator string) {
+	b.operator = operator
+}
+
+// SetEligibility makes borrowing check the patron against the policy before the loan starts
+// A LoanTracker directory such as PatronRegistry also counts the loans of this book
+func (b *Book) SetEligibility(patrons PatronDirectory, policy EligibilityPolicy) {
+	b.patrons = patrons
+	b.eligibility = &policy
+}
+
+// checkEligibility evaluates the borrowing guards for a patron, if any are set
+func (b *Book) checkEligibility(patronID string) error {
+	if b.eligibility == nil {
+		return nil
+	}
+	patron, exists := b.patrons.FindPatron(patronID)
+	if !exists {
+		return &EligibilityError{PatronID: patronID, Reasons: []string{"patron is not registered"}}
+	}
+	return b.eligibility.Check(patron, b.clock.Now())
+}
+
+// startLoanFor counts the loan with the patron directory, checking eligibility again in the same step
+// Directories that do not track loans are only checked by the eligible guard
+func (b *Book) startLoanFor(patronID string) error {
+	tracker, ok := b.patrons.(LoanTracker)
+	if !ok {
+		return nil
+	}
+	return tracker.StartLoan(patronID, func(patron Patron) error {
+		return b.eligibility.Check(patron, b.clock.Now())
+	})
+}
+
+// endLoanFor counts the loan as returned with the patron directory
+func (b *Book) endLoanFor(patronID string) {
+	if tracker, ok := b.patrons.(LoanTracker); ok {
+		tracker.EndLoan(patronID)
+	}
+}
+
+// GetHistory returns the recorded transitions of this copy
+func (b *Book) GetHistory() []Event {
+	return b.history.ForItem(b.barcode)
+}
+
+// SetMachine changes the state machine the book follows
+// The current state is kept, so set the machine before the first transition
+func (b *Book) SetMachine(machine *Machine) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.machine = machine
+}
+
+// Machine returns the state machine the book follows
+func (b *Book) Machine() *Machine {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.machine
+}
+
+// SetState changes the current state directly, bypassing the state machine
+// The change is recorded in the history
 func (b *Book) SetState(state BookState) {
-	b.state = state
+	_ = b.apply(nil, func() error {
+		b.moveTo(state, OpSetState)
+		return nil
+	})
+}
+
+// fire runs an operation through the state machine under the book lock
+func (b *Book) fire(expected *uint64, request Transition) error {
+	return b.apply(expected, func() error {
+		return b.machine.fire(b, request)
+	})
+}
+
+// moveTo changes the current state, records the transition as an event and queues it for publishing
+// Hooks and subscribers run after the change is committed and the lock released, so they cannot undo it
+// Must be called with the book lock held
+func (b *Book) moveTo(next BookState, operation Operation) {
+	previous := b.state
+	b.state = next
+	b.version++
+
+	event := Event{
+		Title:       b.title,
+		ISBN:        b.isbn,
+		Barcode:     b.barcode,
+		From:        previous.Name,
+		To:          next.Name,
+		Operation:   operation,
+		Actor:       b.operator,
+		PatronID:    b.hold,
+		At:          b.clock.Now(),
+		Loan:        next.Loan,
+		Hold:        b.hold,
+		Destination: next.Destination,
+	}
+	if operation != OpPlaceHold {
+		if loan, ok := next.GetLoan(); ok {
+			event.PatronID = loan.BorrowerID
+		} else if loan, ok := previous.GetLoan(); ok {
+			event.PatronID = loan.BorrowerID
+		}
+	}
+	b.pending = append(b.pending, b.history.Append(event.copy()))
+}
+
+// apply runs an operation under the book lock and publishes the transitions it made after unlocking
+// A non-nil expected version makes the operation fail with a ConflictError if the book has moved on
+func (b *Book) apply(expected *uint64, operation func() error) error {
+	b.mu.Lock()
+	var err error
+	if expected != nil && *expected != b.version {
+		err = &ConflictError{ISBN: b.isbn, Expected: *expected, Actual: b.version}
+	} else {
+		err = operation()
+	}
+	events := b.pending
+	b.pending = nil
+	bus := b.events
+	b.mu.Unlock()
+
+	for _, event := range events {
+		bus.Publish(event)
+	}
+	return err
+}
+
+// Version returns the current version of the book, incremented by every transition
+func (b *Book) Version() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.version
+}
+
+// AtVersion returns a view of the book whose operations only succeed while the book is still at version
+func (b *Book) AtVersion(version uint64) *VersionedBook {
+	return &VersionedBook{book: b, version: version}
 }
 
 // GetState returns the current state
 func (b *Book) GetState() BookState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	return b.state
 }
 
-// Borrow attempts to borrow the book
-func (b *Book) Borrow() error {
-	return b.state.Borrow(b)
+// GetLoan returns the current loan if the book is out on loan
+func (b *Book) GetLoan() (Loan, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state.GetLoan()
+}
+
+// GetHold returns the patron holding the book, or an empty string
+func (b *Book) GetHold() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.hold
+}
+
+// newLoan starts a loan for the borrower at the current time
+func (b *Book) newLoan(borrowerID string) Loan {
+	now := b.clock.Now()
+	return Loan{
+		ID:           generateLoanID(),
+		BorrowerID:   borrowerID,
+		CheckedOutAt: now,
+		DueAt:        b.dueDate(now.Add(b.loanPeriod)),
+	}
+}
+
+// chargeFine evaluates the fine policy for a loan that stopped accruing fines at the given time and records the fine
+// Returns false when no fine is due
+func (b *Book) chargeFine(loan Loan, until time.Time) (Fine, bool) {
+	now := b.clock.Now()
+	daysLate := b.daysLate(loan.DueAt, until)
+	amount := b.finePolicyFor(loan).Calculate(b.materialType, daysLate)
+	if amount == 0 {
+		return Fine{}, false
+	}
+	return b.fineLedger.Record(Fine{
+		Kind:     FineOverdue,
+		PatronID: loan.BorrowerID,
+		LoanID:   loan.ID,
+		ISBN:     b.isbn,
+		DaysLate: daysLate,
+		Amount:   amount,
+		IssuedAt: now,
+	}), true
+}
+
+// Borrow attempts to borrow the book for the borrower
+func (b *Book) Borrow(borrowerID string) error {
+	return b.fire(nil, Transition{Operation: OpBorrow, PatronID: borrowerID})
 }
 
 // Return attempts to return the book
 func (b *Book) Return() error {
-	return b.state.Return(b)
+	return b.fire(nil, Transition{Operation: OpReturn})
 }
 
 // MarkOverdue attempts to mark the book as overdue
 func (b *Book) MarkOverdue() error {
-	return b.state.MarkOverdue(b)
+	return b.fire(nil, Transition{Operation: OpMarkOverdue})
+}
+
+// Recall asks the borrower to bring the book back early for another patron
+func (b *Book) Recall(requestedBy string) error {
+	return b.fire(nil, Transition{Operation: OpRecall, PatronID: requestedBy})
+}
+
+// Renew attempts to extend the loan and returns the new due date
+func (b *Book) Renew() (time.Time, error) {
+	return b.renew(nil)
+}
+
+// renew extends the loan under the book lock and returns the new due date
+func (b *Book) renew(expected *uint64) (time.Time, error) {
+	var dueAt time.Time
+	err := b.apply(expected, func() error {
+		if err := b.machine.fire(b, Transition{Operation: OpRenew}); err != nil {
+			return err
+		}
+		loan, _ := b.state.GetLoan()
+		dueAt = loan.DueAt
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return dueAt, nil
+}
+
+// PlaceHold attempts to place a hold on the book for the patron
+func (b *Book) PlaceHold(patronID string) error {
+	return b.fire(nil, Transition{Operation: OpPlaceHold, PatronID: patronID})
+}
+
+// CancelHold attempts to cancel the hold on the book
+func (b *Book) CancelHold() error {
+	return b.fire(nil, Transition{Operation: OpCancelHold})
+}
+
+// Ship attempts to send the book to another branch
+func (b *Book) Ship(destination string) error {
+	return b.fire(nil, Transition{Operation: OpShip, Destination: destination})
+}
+
+// Receive attempts to check the book in after transit or after it was found
+func (b *Book) Receive() error {
+	return b.fire(nil, Transition{Operation: OpReceive})
+}
+
+// DeclareLost attempts to declare the book lost
+func (b *Book) DeclareLost() error {
+	return b.fire(nil, Transition{Operation: OpDeclareLost})
+}
+
+// Withdraw attempts to remove the book from the collection
+func (b *Book) Withdraw() error {
+	return b.fire(nil, Transition{Operation: OpWithdraw})
+}
+
+// ClaimReturned records the borrower's claim that the book was already returned
+func (b *Book) ClaimReturned() error {
+	return b.fire(nil, Transition{Operation: OpClaimReturned})
+}
+
+// ResolveClaim closes a claims returned investigation: a found book is checked in, otherwise it is lost
+func (b *Book) ResolveClaim(found bool) error {
+	return b.apply(nil, func() error {
+		operation := OpDeclareLost
+		if found {
+			operation = OpReceive
+		}
+		if b.state.Name != StateClaimsReturned {
+			return invalidTransition(b.state.Name, operation, "book has no open claims returned")
+		}
+		return b.machine.fire(b, Transition{Operation: operation})
+	})
 }
 
 // GetStateName returns the current state name
 func (b *Book) GetStateName() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	return b.state.GetStateName()
 }
 
-// Display displays book information with current state
-func (b *Book) Display() {
-	fmt.Printf("Book: %s (ISBN: %s)\n", b.title, b.isbn)
-	fmt.Printf("  Current State: %s\n", b.GetStateName())
+// Display writes book information with current state using the given renderer
+func (b *Book) Display(w io.Writer, renderer render.Renderer) error {
+	b.mu.Lock()
+	report := b.report()
+	b.mu.Unlock()
+	return renderer.Render(w, report)
+}
+
+// report describes the book and its current state, must be called with the book lock held
+func (b *Book) report() render.Report {
+	title := fmt.Sprintf("Book: %s (ISBN: %s)", b.title, b.isbn)
+	if b.barcode != b.isbn {
+		title = fmt.Sprintf("Book: %s (ISBN: %s, Barcode: %s)", b.title, b.isbn, b.barcode)
+	}
+	row := []string{b.title, b.isbn, b.barcode, b.state.GetStateName(), "", "", "", "", b.hold}
+	if loan, ok := b.state.GetLoan(); ok {
+		row[4] = loan.BorrowerID
+		row[5] = loan.DueAt.Format(time.DateTime)
+		row[6] = strconv.Itoa(loan.Renewals)
+		row[7] = loan.RecalledBy
+	}
+	return render.Report{
+		Title:   title,
+		Columns: []string{"title", "isbn", "barcode", "state", "borrower", "due_at", "renewals", "recalled_by", "hold"},
+		Rows:    [][]string{row},
+		Line: func(_ int, row []string) string {
+			line := "Current State: " + row[3]
+			if row[4] != "" {
+				line += fmt.Sprintf("\n  Borrower: %s, Due: %s, Renewals: %s", row[4], row[5], row[6])
+			}
+			if row[7] != "" {
+				line += "\n  Recalled for: " + row[7]
+			}
+			if row[8] != "" {
+				line += "\n  Hold for: " + row[8]
+			}
+			return line
+		},
+	}
 }
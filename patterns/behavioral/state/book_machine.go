@@ -0,0 +1,216 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultDefinition returns the circulation state machine of a library copy
+func DefaultDefinition() Definition {
+	return Definition{
+		Initial: StateAvailable,
+		States: []StateDef{
+			{Name: StateAvailable, Label: "available"},
+			{Name: StateBorrowed, Label: "borrowed", CarriesLoan: true},
+			{Name: StateReserved, Label: "borrowed with a hold pending", CarriesLoan: true},
+			{Name: StateOverdue, Label: "overdue", CarriesLoan: true},
+			{Name: StateClaimsReturned, Label: "claimed returned", CarriesLoan: true},
+			{Name: StateOnHoldShelf, Label: "on the hold shelf"},
+			{Name: StateInTransit, Label: "in transit"},
+			{Name: StateLost, Label: "lost", CarriesLoan: true},
+			{Name: StateMissing, Label: "missing"},
+			{Name: StateWithdrawn, Label: "withdrawn"},
+		},
+		Operations: []OperationDef{
+			{Name: OpBorrow, Verb: "borrow"},
+			{Name: OpReturn, Verb: "return"},
+			{Name: OpMarkOverdue, Verb: "mark as overdue"},
+			{Name: OpRenew, Verb: "renew"},
+			{Name: OpRecall, Verb: "recall"},
+			{Name: OpPlaceHold, Verb: "place a hold"},
+			{Name: OpCancelHold, Verb: "cancel a hold"},
+			{Name: OpShip, Verb: "ship"},
+			{Name: OpReceive, Verb: "receive"},
+			{Name: OpDeclareLost, Verb: "declare lost"},
+			{Name: OpWithdraw, Verb: "withdraw"},
+			{Name: OpClaimReturned, Verb: "claim returned"},
+		},
+		Transitions: []TransitionDef{
+			{From: StateAvailable, Operation: OpBorrow, To: StateBorrowed, Guards: []string{"eligible"}, Actions: []string{"startLoan"}},
+			{From: StateAvailable, Operation: OpPlaceHold, To: StateOnHoldShelf, Actions: []string{"placeHold"}, Effects: []string{"reportHold"}},
+			{From: StateAvailable, Operation: OpShip, To: StateInTransit, Actions: []string{"setDestination"}, Effects: []string{"reportDestination"}},
+			{From: StateAvailable, Operation: OpDeclareLost, To: StateMissing},
+			{From: StateAvailable, Operation: OpWithdraw, To: StateWithdrawn},
+
+			{From: StateBorrowed, Operation: OpReturn, To: StateAvailable, Effects: []string{"chargeFine"}},
+			{From: StateBorrowed, Operation: OpMarkOverdue, To: StateOverdue},
+			{From: StateBorrowed, Operation: OpRenew, To: StateBorrowed, Guards: []string{"notRecalled", "renewalsLeft"}, Actions: []string{"extendLoan"}, Effects: []string{"reportDueDate"}},
+			{From: StateBorrowed, Operation: OpRecall, To: StateBorrowed, Actions: []string{"recallLoan"}, Effects: []string{"reportRecall"}},
+			{From: StateBorrowed, Operation: OpPlaceHold, To: StateReserved, Actions: []string{"placeHold"}, Effects: []string{"reportHold"}},
+			{From: StateBorrowed, Operation: OpDeclareLost, To: StateLost, Effects: []string{"billLoss"}},
+			{From: StateBorrowed, Operation: OpClaimReturned, To: StateClaimsReturned, Effects: []string{"openSearch"}},
+
+			{From: StateReserved, Operation: OpReturn, To: StateOnHoldShelf, Effects: []string{"chargeFine", "reportHold"}},
+			{From: StateReserved, Operation: OpMarkOverdue, To: StateOverdue},
+			{From: StateReserved, Operation: OpCancelHold, To: StateBorrowed, Actions: []string{"clearHold"}},
+			{From: StateReserved, Operation: OpRecall, To: StateReserved, Actions: []string{"recallLoan"}, Effects: []string{"reportRecall"}},
+			{From: StateReserved, Operation: OpDeclareLost, To: StateLost, Actions: []string{"clearHold"}, Effects: []string{"billLoss"}},
+			{From: StateReserved, Operation: OpClaimReturned, To: StateClaimsReturned, Effects: []string{"openSearch"}},
+
+			{From: StateOverdue, Operation: OpReturn, To: StateOnHoldShelf, Guards: []string{"holdPending"}, Effects: []string{"chargeFine", "reportHold"}},
+			{From: StateOverdue, Operation: OpReturn, To: StateAvailable, Effects: []string{"chargeFine"}},
+			{From: StateOverdue, Operation: OpDeclareLost, To: StateLost, Actions: []string{"clearHold"}, Effects: []string{"billLoss"}},
+			{From: StateOverdue, Operation: OpClaimReturned, To: StateClaimsReturned, Effects: []string{"openSearch"}},
+
+			{From: StateClaimsReturned, Operation: OpReceive, To: StateOnHoldShelf, Guards: []string{"holdPending"}, Effects: []string{"chargeFineToClaim", "closeSearchFound", "reportHold"}},
+			{From: StateClaimsReturned, Operation: OpReceive, To: StateAvailable, Effects: []string{"chargeFineToClaim", "closeSearchFound"}},
+			{From: StateClaimsReturned, Operation: OpDeclareLost, To: StateLost, Actions: []string{"clearHold"}, Effects: []string{"closeSearchNotFound", "billLoss"}},
+
+			{From: StateOnHoldShelf, Operation: OpBorrow, To: StateBorrowed, Guards: []string{"holdPatron", "eligible"}, Actions: []string{"clearHold", "startLoan"}},
+			{From: StateOnHoldShelf, Operation: OpCancelHold, To: StateAvailable, Actions: []string{"clearHold"}},
+			{From: StateOnHoldShelf, Operation: OpShip, To: StateInTransit, Actions: []string{"setDestination"}, Effects: []string{"reportDestination"}},
+			{From: StateOnHoldShelf, Operation: OpDeclareLost, To: StateMissing, Actions: []string{"clearHold"}},
+
+			{From: StateInTransit, Operation: OpReceive, To: StateOnHoldShelf, Guards: []string{"holdPending"}, Effects: []string{"reportHold"}},
+			{From: StateInTransit, Operation: OpReceive, To: StateAvailable},
+			{From: StateInTransit, Operation: OpDeclareLost, To: StateMissing, Actions: []string{"clearHold"}},
+
+			{From: StateLost, Operation: OpReturn, To: StateAvailable, Effects: []string{"reverseLoss"}},
+			{From: StateLost, Operation: OpWithdraw, To: StateWithdrawn},
+
+			{From: StateMissing, Operation: OpReceive, To: StateAvailable},
+			{From: StateMissing, Operation: OpWithdraw, To: StateWithdrawn},
+		},
+	}
+}
+
+// DefaultBehaviors returns the guards, actions and effects used by DefaultDefinition
+// Add entries to the returned maps to reference new behaviors from a custom definition
+func DefaultBehaviors() Behaviors {
+	return Behaviors{
+		Guards: map[string]TransitionGuard{
+			"eligible": func(t *Transition) error {
+				return t.Book.checkEligibility(t.PatronID)
+			},
+			"holdPatron": func(t *Transition) error {
+				if t.PatronID != t.Book.hold {
+					return rejectTransition(t, fmt.Sprintf("book is on the hold shelf for %s", t.Book.hold))
+				}
+				return nil
+			},
+			"holdPending": func(t *Transition) error {
+				if t.Book.hold == "" {
+					return rejectTransition(t, "no hold is pending")
+				}
+				return nil
+			},
+			"notRecalled": func(t *Transition) error {
+				if loan, _ := t.From.GetLoan(); loan.IsRecalled() {
+					return rejectTransition(t, "cannot renew a recalled book")
+				}
+				return nil
+			},
+			"renewalsLeft": func(t *Transition) error {
+				loan, _ := t.From.GetLoan()
+				policy := t.Book.renewalPolicy
+				if loan.Renewals >= policy.MaxRenewals {
+					return fmt.Errorf("cannot renew '%s': %w (%d of %d used)",
+						t.Book.GetTitle(), ErrRenewalLimitReached, loan.Renewals, policy.MaxRenewals)
+				}
+				return nil
+			},
+		},
+		Actions: map[string]TransitionAction{
+			"startLoan": func(t *Transition) error {
+				if err := t.Book.startLoanFor(t.PatronID); err != nil {
+					return err
+				}
+				t.To = t.To.withLoan(t.Book.newLoan(t.PatronID))
+				return nil
+			},
+			"extendLoan": func(t *Transition) error {
+				loan, _ := t.To.GetLoan()
+				loan.Renewals++
+				loan.DueAt = t.Book.dueDate(t.Book.renewalPolicy.NextDueDate(loan.DueAt, t.Book.clock.Now(), t.Book.loanPeriod))
+				t.To = t.To.withLoan(loan)
+				return nil
+			},
+			"recallLoan": func(t *Transition) error {
+				loan, _ := t.To.GetLoan()
+				recalled, err := t.Book.recallLoan(loan, t.PatronID)
+				if err != nil {
+					return err
+				}
+				t.To = t.To.withLoan(recalled)
+				return nil
+			},
+			"placeHold": func(t *Transition) error {
+				t.Hold = t.PatronID
+				return nil
+			},
+			"clearHold": func(t *Transition) error {
+				t.Hold = ""
+				return nil
+			},
+			"setDestination": func(t *Transition) error {
+				t.To.Destination = t.Destination
+				return nil
+			},
+		},
+		Effects: map[string]TransitionEffect{
+			"chargeFine": func(t *Transition) {
+				loan, _ := t.From.GetLoan()
+				if fine, charged := t.Book.chargeFine(loan, t.Book.clock.Now()); charged {
+					t.Book.log("fine charged", "fine", fine.String())
+				}
+			},
+			// chargeFineToClaim charges the fine accrued until the claim, fines are suspended while the search is open
+			"chargeFineToClaim": func(t *Transition) {
+				loan, _ := t.From.GetLoan()
+				task, open := t.Book.claims.openTask(t.Book.barcode)
+				if !open {
+					return
+				}
+				if fine, charged := t.Book.chargeFine(loan, task.OpenedAt); charged {
+					t.Book.log("fine charged up to claim", "fine", fine.String())
+				}
+			},
+			"billLoss": func(t *Transition) {
+				loan, _ := t.From.GetLoan()
+				for _, bill := range t.Book.billLoss(loan) {
+					t.Book.log("billed", "fine", bill.String())
+				}
+			},
+			"reverseLoss": func(t *Transition) {
+				loan, _ := t.From.GetLoan()
+				for _, reversal := range t.Book.reverseLoss(loan) {
+					t.Book.log("reversed", "fine", reversal.String())
+				}
+			},
+			"openSearch": func(t *Transition) {
+				loan, _ := t.From.GetLoan()
+				t.Book.openSearch(loan)
+			},
+			"closeSearchFound": func(t *Transition) {
+				t.Book.claims.closeSearch(t.Book.barcode, true, t.Book.clock.Now())
+			},
+			"closeSearchNotFound": func(t *Transition) {
+				t.Book.claims.closeSearch(t.Book.barcode, false, t.Book.clock.Now())
+			},
+			"reportHold": func(t *Transition) {
+				t.Book.log("hold pending", "patron", t.Hold)
+			},
+			"reportDestination": func(t *Transition) {
+				t.Book.log("shipped", "destination", t.To.Destination)
+			},
+			"reportDueDate": func(t *Transition) {
+				loan, _ := t.To.GetLoan()
+				t.Book.log("loan renewed", "due_at", loan.DueAt.Format(time.DateTime))
+			},
+			"reportRecall": func(t *Transition) {
+				loan, _ := t.To.GetLoan()
+				t.Book.log("loan recalled", "recalled_by", loan.RecalledBy, "due_at", loan.DueAt.Format(time.DateTime))
+			},
+		},
+	}
+}
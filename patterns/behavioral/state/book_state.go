@@ -1,9 +1,38 @@
 package state
 
-// BookState interface defines methods for all book states
-type BookState interface {
-	Borrow(book *Book) error
-	Return(book *Book) error
-	MarkOverdue(book *Book) error
-	GetStateName() string
+// BookState is the state a book is in, with the loan and destination the state carries
+// Which operations a state allows is defined by the state machine, not by the state itself
+type BookState struct {
+	Name        string
+	Loan        *Loan
+	Destination string
+}
+
+// NewState creates a state without a loan
+func NewState(name string) BookState {
+	return BookState{Name: name}
+}
+
+// NewLoanState creates a state carrying a loan, e.g. Borrowed or Overdue
+func NewLoanState(name string, loan Loan) BookState {
+	return BookState{Name: name, Loan: &loan}
+}
+
+// GetStateName returns state name
+func (bs BookState) GetStateName() string {
+	return bs.Name
+}
+
+// GetLoan returns the loan carried by the state, if any
+func (bs BookState) GetLoan() (Loan, bool) {
+	if bs.Loan == nil {
+		return Loan{}, false
+	}
+	return *bs.Loan, true
+}
+
+// withLoan returns the state carrying its own copy of the loan
+func (bs BookState) withLoan(loan Loan) BookState {
+	bs.Loan = &loan
+	return bs
 }
@@ -0,0 +1,130 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestBook creates a book with a fixed clock that does not log
+func newTestBook(title, isbn string) *Book {
+	book := NewBook(title, isbn)
+	book.SetClock(NewManualClock(time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)))
+	book.SetLogger(slog.New(slog.DiscardHandler))
+	return book
+}
+
+func TestConcurrentBorrowLendsOnce(t *testing.T) {
+	const desks = 64
+	book := newTestBook("Atomic Habits", "9780735211292")
+
+	var wg sync.WaitGroup
+	errs := make([]error, desks)
+	for i := range desks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = book.Borrow(fmt.Sprintf("desk-%d", i))
+		}()
+	}
+	wg.Wait()
+
+	winner := -1
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			if winner >= 0 {
+				t.Fatalf("desk-%d and desk-%d both borrowed the book", winner, i)
+			}
+			winner = i
+		case !errors.Is(err, ErrInvalidTransition):
+			t.Errorf("desk-%d: unexpected error %v", i, err)
+		}
+	}
+	if winner < 0 {
+		t.Fatal("no desk borrowed the book")
+	}
+	loan, ok := book.GetLoan()
+	if !ok || loan.BorrowerID != fmt.Sprintf("desk-%d", winner) {
+		t.Errorf("loan = %+v, want borrower desk-%d", loan, winner)
+	}
+	if history := book.GetHistory(); len(history) != 1 {
+		t.Errorf("history has %d events, want 1", len(history))
+	}
+	if version := book.Version(); version != 1 {
+		t.Errorf("version = %d, want 1", version)
+	}
+}
+
+func TestConcurrentAtVersionAllowsOneWriter(t *testing.T) {
+	const desks = 64
+	book := newTestBook("Atomic Habits", "9780735211292")
+	if err := book.Borrow("student-123"); err != nil {
+		t.Fatal(err)
+	}
+	version := book.Version()
+
+	var wg sync.WaitGroup
+	errs := make([]error, desks)
+	for i := range desks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = book.AtVersion(version).Return()
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for i, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		var conflict *ConflictError
+		if !errors.Is(err, ErrConflict) || !errors.As(err, &conflict) {
+			t.Errorf("desk %d: got %v, want a conflict", i, err)
+			continue
+		}
+		if conflict.Expected != version || conflict.Actual != version+1 {
+			t.Errorf("desk %d: conflict %d/%d, want %d/%d", i, conflict.Expected, conflict.Actual, version, version+1)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d returns succeeded, want exactly 1", succeeded)
+	}
+	if name := book.GetStateName(); name != StateAvailable {
+		t.Errorf("state = %s, want %s", name, StateAvailable)
+	}
+}
+
+func TestConcurrentLoansOfDifferentBooksGetUniqueIDs(t *testing.T) {
+	const books = 8
+	ids := make([]string, books)
+	var wg sync.WaitGroup
+	for i := range books {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			book := newTestBook(fmt.Sprintf("Book %d", i), fmt.Sprintf("isbn-%d", i))
+			if err := book.Borrow("student-123"); err != nil {
+				t.Error(err)
+				return
+			}
+			loan, _ := book.GetLoan()
+			ids[i] = loan.ID
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if seen[id] {
+			t.Errorf("loan ID %s handed out twice", id)
+		}
+		seen[id] = true
+	}
+}
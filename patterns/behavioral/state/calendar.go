@@ -0,0 +1,187 @@
+package state
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// OpeningHours is the time a branch opens and closes, as offsets from midnight
+type OpeningHours struct {
+	Open  time.Duration
+	Close time.Duration
+}
+
+// Closure is an ad-hoc period the branch is closed, e.g. for stocktaking
+type Closure struct {
+	Start  time.Time
+	End    time.Time
+	Reason string
+}
+
+// Calendar holds the opening hours, holidays and closures of one branch
+// Days without opening hours, holidays and days fully covered by a closure are closed days
+type Calendar struct {
+	branch   string
+	location *time.Location
+	weekly   map[time.Weekday]OpeningHours
+	holidays map[string]string
+	annual   []annualHoliday
+	closures []Closure
+}
+
+// annualHoliday is a holiday on the same date every year from a given year on
+type annualHoliday struct {
+	month time.Month
+	day   int
+	from  int
+	name  string
+}
+
+// NewCalendar creates a calendar open Monday to Friday 09:00-17:00 and Saturday 09:00-13:00
+// A nil location means UTC
+func NewCalendar(branch string, location *time.Location) *Calendar {
+	if location == nil {
+		location = time.UTC
+	}
+	calendar := &Calendar{
+		branch:   branch,
+		location: location,
+		weekly:   make(map[time.Weekday]OpeningHours),
+		holidays: make(map[string]string),
+	}
+	for day := time.Monday; day <= time.Friday; day++ {
+		calendar.SetHours(day, 9*time.Hour, 17*time.Hour)
+	}
+	calendar.SetHours(time.Saturday, 9*time.Hour, 13*time.Hour)
+	return calendar
+}
+
+// GetBranch returns the branch the calendar belongs to
+func (c *Calendar) GetBranch() string {
+	return c.branch
+}
+
+// SetHours sets the weekly opening hours of a weekday
+func (c *Calendar) SetHours(day time.Weekday, open, close time.Duration) {
+	c.weekly[day] = OpeningHours{Open: open, Close: close}
+}
+
+// CloseWeekday removes the weekly opening hours of a weekday
+func (c *Calendar) CloseWeekday(day time.Weekday) {
+	delete(c.weekly, day)
+}
+
+// AddHoliday marks a date as a holiday
+func (c *Calendar) AddHoliday(date time.Time, name string) {
+	c.holidays[c.dateKey(date)] = name
+}
+
+// AddClosure adds an ad-hoc closure
+func (c *Calendar) AddClosure(start, end time.Time, reason string) error {
+	if !end.After(start) {
+		return fmt.Errorf("closure '%s' ends before it starts", reason)
+	}
+	c.closures = append(c.closures, Closure{Start: start, End: end, Reason: reason})
+	sort.Slice(c.closures, func(i, j int) bool {
+		return c.closures[i].Start.Before(c.closures[j].Start)
+	})
+	return nil
+}
+
+// AddAnnualHoliday marks the same date as a holiday every year, starting with the year of from
+func (c *Calendar) AddAnnualHoliday(from time.Time, name string) {
+	local := from.In(c.location)
+	c.annual = append(c.annual, annualHoliday{month: local.Month(), day: local.Day(), from: local.Year(), name: name})
+}
+
+// Holiday returns the name of the holiday on the date, if any
+func (c *Calendar) Holiday(date time.Time) (string, bool) {
+	if name, exists := c.holidays[c.dateKey(date)]; exists {
+		return name, true
+	}
+	year, month, day := date.In(c.location).Date()
+	for _, holiday := range c.annual {
+		if holiday.month == month && holiday.day == day && year >= holiday.from {
+			return holiday.name, true
+		}
+	}
+	return "", false
+}
+
+// HoursOn returns the opening hours of the date, false if the branch is closed all day
+func (c *Calendar) HoursOn(date time.Time) (open, close time.Time, ok bool) {
+	hours, exists := c.weekly[date.In(c.location).Weekday()]
+	if !exists {
+		return time.Time{}, time.Time{}, false
+	}
+	if _, holiday := c.Holiday(date); holiday {
+		return time.Time{}, time.Time{}, false
+	}
+	midnight := c.midnight(date)
+	open, close = midnight.Add(hours.Open), midnight.Add(hours.Close)
+	for _, closure := range c.closures {
+		if !closure.Start.After(open) && !closure.End.Before(close) {
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	return open, close, true
+}
+
+// IsOpenDay checks if the branch opens at all on the date
+func (c *Calendar) IsOpenDay(date time.Time) bool {
+	_, _, ok := c.HoursOn(date)
+	return ok
+}
+
+// NextOpenDay returns the closing time of the first open day on or after the date
+// Gives up after a year of closed days and returns the date unchanged
+func (c *Calendar) NextOpenDay(date time.Time) time.Time {
+	day := c.midnight(date)
+	for i := 0; i < 366; i++ {
+		if _, close, ok := c.HoursOn(day); ok {
+			return close
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// RollDueDate moves a due date that falls on a closed day to closing time of the next open day
+func (c *Calendar) RollDueDate(dueAt time.Time) time.Time {
+	if c.IsOpenDay(dueAt) {
+		return dueAt
+	}
+	return c.NextOpenDay(dueAt)
+}
+
+// ClosedDaysBetween counts the closed days after the date of from, up to and including the date of to
+func (c *Calendar) ClosedDaysBetween(from, to time.Time) int {
+	closed := 0
+	for day := c.midnight(from).AddDate(0, 0, 1); !day.After(to); day = day.AddDate(0, 0, 1) {
+		if !c.IsOpenDay(day) {
+			closed++
+		}
+	}
+	return closed
+}
+
+// DaysLate returns the days late like DaysLate, without the days the branch was closed
+func (c *Calendar) DaysLate(dueAt, returnedAt time.Time) int {
+	days := DaysLate(dueAt, returnedAt) - c.ClosedDaysBetween(dueAt, returnedAt)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// midnight returns the start of the date in the calendar's location
+func (c *Calendar) midnight(date time.Time) time.Time {
+	year, month, day := date.In(c.location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, c.location)
+}
+
+// dateKey returns the date in the calendar's location as YYYY-MM-DD
+func (c *Calendar) dateKey(date time.Time) string {
+	return date.In(c.location).Format(time.DateOnly)
+}
@@ -0,0 +1,155 @@
+package state
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultRepeatClaimThreshold is the number of claims after which a patron is flagged
+const DefaultRepeatClaimThreshold = 3
+
+// SearchTask asks staff to search the shelves for a book a patron claims to have returned
+type SearchTask struct {
+	ID       string
+	ISBN     string
+	Barcode  string
+	Title    string
+	PatronID string
+	LoanID   string
+	OpenedAt time.Time
+	ClosedAt time.Time
+	Found    bool
+}
+
+// IsOpen checks if the search has not been resolved yet
+func (st SearchTask) IsOpen() bool {
+	return st.ClosedAt.IsZero()
+}
+
+// String returns a human readable description of the search task
+func (st SearchTask) String() string {
+	status := "open"
+	if !st.IsOpen() {
+		status = "not found"
+		if st.Found {
+			status = "found"
+		}
+	}
+	return fmt.Sprintf("%s: search shelves for '%s' (%s) claimed returned by %s, %s",
+		st.ID, st.Title, st.Barcode, st.PatronID, status)
+}
+
+// ClaimLedger keeps shelf search tasks and claims-returned counts per patron, safe for concurrent use
+type ClaimLedger struct {
+	mu        sync.Mutex
+	tasks     []SearchTask
+	claims    map[string]int
+	threshold int
+}
+
+// NewClaimLedger creates an empty claim ledger flagging patrons at the default threshold
+func NewClaimLedger() *ClaimLedger {
+	return &ClaimLedger{claims: make(map[string]int), threshold: DefaultRepeatClaimThreshold}
+}
+
+// SetRepeatThreshold changes the number of claims after which a patron is flagged
+func (cl *ClaimLedger) SetRepeatThreshold(threshold int) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.threshold = threshold
+}
+
+// openSearch counts the claim and opens a shelf search task for it
+func (cl *ClaimLedger) openSearch(task SearchTask) SearchTask {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	task.ID = fmt.Sprintf("ST-%d", len(cl.tasks)+1)
+	cl.tasks = append(cl.tasks, task)
+	cl.claims[task.PatronID]++
+	return task
+}
+
+// openTask returns the open search task of a copy
+func (cl *ClaimLedger) openTask(barcode string) (SearchTask, bool) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	for _, task := range cl.tasks {
+		if task.Barcode == barcode && task.IsOpen() {
+			return task, true
+		}
+	}
+	return SearchTask{}, false
+}
+
+// closeSearch resolves the open search task of a copy
+func (cl *ClaimLedger) closeSearch(barcode string, found bool, at time.Time) (SearchTask, bool) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	for i := range cl.tasks {
+		if cl.tasks[i].Barcode == barcode && cl.tasks[i].IsOpen() {
+			cl.tasks[i].ClosedAt = at
+			cl.tasks[i].Found = found
+			return cl.tasks[i], true
+		}
+	}
+	return SearchTask{}, false
+}
+
+// OpenTasks returns the searches not resolved yet
+func (cl *ClaimLedger) OpenTasks() []SearchTask {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	tasks := make([]SearchTask, 0)
+	for _, task := range cl.tasks {
+		if task.IsOpen() {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// Claims returns how many times the patron claimed to have returned a book
+func (cl *ClaimLedger) Claims(patronID string) int {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.claims[patronID]
+}
+
+// IsFlagged checks if the patron reached the repeat claim threshold
+func (cl *ClaimLedger) IsFlagged(patronID string) bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.claims[patronID] >= cl.threshold
+}
+
+// Flagged returns the repeat claimants sorted by ID
+func (cl *ClaimLedger) Flagged() []string {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	flagged := make([]string, 0)
+	for patronID, count := range cl.claims {
+		if count >= cl.threshold {
+			flagged = append(flagged, patronID)
+		}
+	}
+	sort.Strings(flagged)
+	return flagged
+}
+
+// openSearch counts the borrower's claim and opens a shelf search for the book
+func (b *Book) openSearch(loan Loan) {
+	task := b.claims.openSearch(SearchTask{
+		ISBN:     b.isbn,
+		Barcode:  b.barcode,
+		Title:    b.title,
+		PatronID: loan.BorrowerID,
+		LoanID:   loan.ID,
+		OpenedAt: b.clock.Now(),
+	})
+	b.log("fines suspended, search task opened", "task", task.String())
+	if b.claims.IsFlagged(loan.BorrowerID) {
+		b.log("patron flagged for repeat claims", "patron", loan.BorrowerID, "claims", b.claims.Claims(loan.BorrowerID))
+	}
+}
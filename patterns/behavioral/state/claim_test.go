@@ -0,0 +1,44 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFoundClaimChargesFineUpToClaim(t *testing.T) {
+	clock := NewManualClock(time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC))
+	fines := NewFineLedger()
+	book := newTestBook("Sapiens", "9780062316097")
+	book.SetClock(clock)
+	book.SetFineLedger(fines)
+
+	if err := book.Borrow("student-123"); err != nil {
+		t.Fatal(err)
+	}
+	loan, _ := book.GetLoan()
+	clock.Advance(30 * 24 * time.Hour)
+	if err := book.MarkOverdue(); err != nil {
+		t.Fatal(err)
+	}
+	if err := book.ClaimReturned(); err != nil {
+		t.Fatal(err)
+	}
+	claimedAt := clock.Now()
+	// the search takes a while, fines do not accrue meanwhile
+	clock.Advance(10 * 24 * time.Hour)
+	if err := book.ResolveClaim(true); err != nil {
+		t.Fatal(err)
+	}
+
+	recorded := fines.ForPatron("student-123")
+	if len(recorded) != 1 {
+		t.Fatalf("%d fines recorded, want 1", len(recorded))
+	}
+	daysLate := DaysLate(loan.DueAt, claimedAt)
+	if want := book.finePolicy.Calculate(book.materialType, daysLate); recorded[0].Amount != want || recorded[0].DaysLate != daysLate {
+		t.Errorf("fine = %d for %d day(s), want %d for %d day(s)", recorded[0].Amount, recorded[0].DaysLate, want, daysLate)
+	}
+	if tasks := book.claims.OpenTasks(); len(tasks) != 0 {
+		t.Errorf("%d searches still open, want 0", len(tasks))
+	}
+}
@@ -0,0 +1,41 @@
+package state
+
+import "time"
+
+// Clock provides the current time so due dates can be tested and simulated
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is a clock backed by the system time
+type SystemClock struct{}
+
+// Now returns the current system time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ManualClock is a clock that only moves when told to
+type ManualClock struct {
+	now time.Time
+}
+
+// NewManualClock creates a manual clock set to the given time
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the time the clock is set to
+func (mc *ManualClock) Now() time.Time {
+	return mc.now
+}
+
+// Set moves the clock to the given time
+func (mc *ManualClock) Set(now time.Time) {
+	mc.now = now
+}
+
+// Advance moves the clock forward by the given duration
+func (mc *ManualClock) Advance(d time.Duration) {
+	mc.now = mc.now.Add(d)
+}
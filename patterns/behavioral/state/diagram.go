@@ -0,0 +1,63 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Diagram formats accepted by RenderDiagram
+const (
+	DiagramDOT     = "dot"
+	DiagramMermaid = "mermaid"
+)
+
+// RenderDiagram renders the definition as a Graphviz DOT or Mermaid state diagram
+func (d Definition) RenderDiagram(format string) (string, error) {
+	switch format {
+	case DiagramDOT:
+		return d.renderDOT(), nil
+	case DiagramMermaid:
+		return d.renderMermaid(), nil
+	}
+	return "", fmt.Errorf("unknown diagram format '%s', expected %s or %s", format, DiagramDOT, DiagramMermaid)
+}
+
+// renderDOT renders the definition as a Graphviz digraph
+func (d Definition) renderDOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph BookState {\n")
+	sb.WriteString("\trankdir=LR;\n")
+	sb.WriteString("\tnode [shape=box, style=rounded];\n")
+	sb.WriteString("\tstart [shape=point];\n")
+	for _, name := range d.TerminalStates() {
+		fmt.Fprintf(&sb, "\t%s [peripheries=2];\n", name)
+	}
+	fmt.Fprintf(&sb, "\tstart -> %s;\n", d.Initial)
+	for _, transition := range d.Transitions {
+		fmt.Fprintf(&sb, "\t%s -> %s [label=\"%s\"];\n", transition.From, transition.To, transition.label())
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// renderMermaid renders the definition as a Mermaid stateDiagram
+func (d Definition) renderMermaid() string {
+	var sb strings.Builder
+	sb.WriteString("stateDiagram-v2\n")
+	fmt.Fprintf(&sb, "    [*] --> %s\n", d.Initial)
+	for _, transition := range d.Transitions {
+		fmt.Fprintf(&sb, "    %s --> %s : %s\n", transition.From, transition.To, transition.label())
+	}
+	for _, name := range d.TerminalStates() {
+		fmt.Fprintf(&sb, "    %s --> [*]\n", name)
+	}
+	return sb.String()
+}
+
+// label returns the operation name with its guards, if any
+func (t TransitionDef) label() string {
+	if len(t.Guards) == 0 {
+		return string(t.Operation)
+	}
+	return fmt.Sprintf("%s [%s]", t.Operation, strings.Join(t.Guards, ", "))
+}
@@ -0,0 +1,133 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"library-management-system/internal/money"
+)
+
+// ErrNotEligible is matched by every error returned when a patron may not borrow
+var ErrNotEligible = errors.New("patron is not eligible to borrow")
+
+// EligibilityError reports every reason a patron may not borrow
+type EligibilityError struct {
+	PatronID string
+	Reasons  []string
+}
+
+// Error returns the patron and all failing reasons
+func (ee *EligibilityError) Error() string {
+	return fmt.Sprintf("patron %s may not borrow: %s", ee.PatronID, strings.Join(ee.Reasons, "; "))
+}
+
+// Is makes errors.Is(err, ErrNotEligible) match every EligibilityError
+func (ee *EligibilityError) Is(target error) bool {
+	return target == ErrNotEligible
+}
+
+// Guard checks borrowing conditions for a patron and returns the reasons they fail
+type Guard func(patron Patron, now time.Time) []string
+
+// AllOf combines guards into one guard reporting the failing reasons of all of them
+func AllOf(guards ...Guard) Guard {
+	return func(patron Patron, now time.Time) []string {
+		reasons := make([]string, 0)
+		for _, guard := range guards {
+			reasons = append(reasons, guard(patron, now)...)
+		}
+		return reasons
+	}
+}
+
+// ActivePatron fails for suspended or closed memberships
+func ActivePatron() Guard {
+	return func(patron Patron, now time.Time) []string {
+		if !patron.Active {
+			return []string{"membership is not active"}
+		}
+		return nil
+	}
+}
+
+// CardNotExpired fails when the library card has expired
+func CardNotExpired() Guard {
+	return func(patron Patron, now time.Time) []string {
+		if !patron.CardExpiresAt.IsZero() && !now.Before(patron.CardExpiresAt) {
+			return []string{fmt.Sprintf("card expired on %s", patron.CardExpiresAt.Format(time.DateOnly))}
+		}
+		return nil
+	}
+}
+
+// UnderLoanLimit fails when the patron already has limit loans out
+func UnderLoanLimit(limit int) Guard {
+	return func(patron Patron, now time.Time) []string {
+		if patron.LoansOut >= limit {
+			return []string{fmt.Sprintf("%d loans out, limit is %d", patron.LoansOut, limit)}
+		}
+		return nil
+	}
+}
+
+// FinesAtMost fails when the patron owes more than threshold in fines
+func FinesAtMost(threshold int64) Guard {
+	return func(patron Patron, now time.Time) []string {
+		if patron.FinesOwed > threshold {
+			return []string{fmt.Sprintf("owes %s in fines, threshold is %s",
+				money.Format(patron.FinesOwed), money.Format(threshold))}
+		}
+		return nil
+	}
+}
+
+// CategoryRules are the borrowing limits of one patron category
+// Extra guards are checked in addition to the standard ones
+type CategoryRules struct {
+	LoanLimit int
+	MaxFines  int64
+	Extra     []Guard
+}
+
+// Guard returns the standard guards for these rules combined with the extra ones
+func (cr CategoryRules) Guard() Guard {
+	guards := []Guard{ActivePatron(), CardNotExpired(), UnderLoanLimit(cr.LoanLimit), FinesAtMost(cr.MaxFines)}
+	return AllOf(append(guards, cr.Extra...)...)
+}
+
+// EligibilityPolicy holds the borrowing rules per patron category
+// Categories without rules of their own use Default
+type EligibilityPolicy struct {
+	Default    CategoryRules
+	Categories map[string]CategoryRules
+}
+
+// DefaultEligibilityPolicy returns the rules used for students, staff and everyone else
+func DefaultEligibilityPolicy() EligibilityPolicy {
+	return EligibilityPolicy{
+		Default: CategoryRules{LoanLimit: 5, MaxFines: 1000},
+		Categories: map[string]CategoryRules{
+			"student": {LoanLimit: 3, MaxFines: 500},
+			"staff":   {LoanLimit: 10, MaxFines: 2000},
+		},
+	}
+}
+
+// RulesFor returns the rules of a patron category
+func (ep EligibilityPolicy) RulesFor(category string) CategoryRules {
+	if rules, exists := ep.Categories[category]; exists {
+		return rules
+	}
+	return ep.Default
+}
+
+// Check returns an EligibilityError listing every reason the patron may not borrow now
+func (ep EligibilityPolicy) Check(patron Patron, now time.Time) error {
+	reasons := ep.RulesFor(patron.Category).Guard()(patron, now)
+	if len(reasons) > 0 {
+		return &EligibilityError{PatronID: patron.ID, Reasons: reasons}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidTransition is matched by every error returned for a disallowed operation
+var ErrInvalidTransition = errors.New("invalid state transition")
+
+// ErrRenewalLimitReached is returned when a loan has used all of its renewals
+var ErrRenewalLimitReached = errors.New("renewal limit reached")
+
+// TransitionError reports an operation the current state does not allow
+type TransitionError struct {
+	State     string
+	Operation Operation
+	Reason    string
+}
+
+// Error returns the reason the operation was rejected
+func (te *TransitionError) Error() string {
+	return te.Reason
+}
+
+// Is makes errors.Is(err, ErrInvalidTransition) match every TransitionError
+func (te *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// invalidTransition creates a TransitionError for the given state and operation
+func invalidTransition(state string, operation Operation, reason string) error {
+	return &TransitionError{State: state, Operation: operation, Reason: reason}
+}
+
+// ErrConflict is matched by every error returned for an operation on a stale version of a book
+var ErrConflict = errors.New("book was changed by another operation")
+
+// ConflictError reports an operation made against a version of the book that is no longer current
+type ConflictError struct {
+	ISBN     string
+	Expected uint64
+	Actual   uint64
+}
+
+// Error returns the expected and the current version of the book
+func (ce *ConflictError) Error() string {
+	return fmt.Sprintf("book '%s' is at version %d, expected version %d", ce.ISBN, ce.Actual, ce.Expected)
+}
+
+// Is makes errors.Is(err, ErrConflict) match every ConflictError
+func (ce *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
@@ -0,0 +1,155 @@
+package state
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Handler reacts to a state transition, a returned error is reported but never undoes the transition
+type Handler func(event Event) error
+
+// SubscriberError records a handler that failed while handling an event
+type SubscriberError struct {
+	Event Event
+	Err   error
+}
+
+// Error returns the failure with the event it happened on
+func (se SubscriberError) Error() string {
+	return fmt.Sprintf("subscriber failed on %s: %s", se.Event, se.Err)
+}
+
+// EventBus delivers state transitions to OnExit/OnEnter hooks and subscribers
+// Handlers run synchronously after the transition is committed; channel subscribers
+// receive events through a buffered channel and miss events while their buffer is full
+type EventBus struct {
+	mu          sync.Mutex
+	enterHooks  map[string][]Handler
+	exitHooks   map[string][]Handler
+	subscribers []Handler
+	channels    []chan Event
+	failures    []SubscriberError
+	dropped     int
+	closed      bool
+}
+
+// NewEventBus creates an event bus without subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{
+		enterHooks: make(map[string][]Handler),
+		exitHooks:  make(map[string][]Handler),
+	}
+}
+
+// OnEnter registers a hook run whenever a book enters the named state
+func (eb *EventBus) OnEnter(stateName string, hook Handler) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.enterHooks[stateName] = append(eb.enterHooks[stateName], hook)
+}
+
+// OnExit registers a hook run whenever a book leaves the named state
+func (eb *EventBus) OnExit(stateName string, hook Handler) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.exitHooks[stateName] = append(eb.exitHooks[stateName], hook)
+}
+
+// Subscribe registers a handler run synchronously for every transition
+func (eb *EventBus) Subscribe(handler Handler) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.subscribers = append(eb.subscribers, handler)
+}
+
+// SubscribeChannel returns a buffered channel receiving every transition
+// After Close the returned channel is already closed
+func (eb *EventBus) SubscribeChannel(buffer int) <-chan Event {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	channel := make(chan Event, buffer)
+	if eb.closed {
+		close(channel)
+		return channel
+	}
+	eb.channels = append(eb.channels, channel)
+	return channel
+}
+
+// Close closes every channel subscription, later events still reach hooks and handlers
+func (eb *EventBus) Close() {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	if eb.closed {
+		return
+	}
+	eb.closed = true
+	for _, channel := range eb.channels {
+		close(channel)
+	}
+	eb.channels = nil
+}
+
+// Publish delivers the event to exit hooks, enter hooks, subscribers and channels in that order
+func (eb *EventBus) Publish(event Event) {
+	eb.mu.Lock()
+	handlers := make([]Handler, 0)
+	handlers = append(handlers, eb.exitHooks[event.From]...)
+	handlers = append(handlers, eb.enterHooks[event.To]...)
+	handlers = append(handlers, eb.subscribers...)
+	eb.mu.Unlock()
+
+	for _, handler := range handlers {
+		if err := eb.call(handler, event.copy()); err != nil {
+			eb.recordFailure(SubscriberError{Event: event, Err: err})
+		}
+	}
+	eb.send(event)
+}
+
+// send offers the event to every channel without blocking
+// It holds the lock so Close cannot close a channel in the middle of a send
+func (eb *EventBus) send(event Event) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	for _, channel := range eb.channels {
+		select {
+		case channel <- event.copy():
+		default:
+			eb.dropped++
+		}
+	}
+}
+
+// Failures returns the handler failures recorded so far
+func (eb *EventBus) Failures() []SubscriberError {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	failures := make([]SubscriberError, len(eb.failures))
+	copy(failures, eb.failures)
+	return failures
+}
+
+// Dropped returns how many events were not delivered because a channel buffer was full
+func (eb *EventBus) Dropped() int {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	return eb.dropped
+}
+
+// call runs a handler and turns a panic into an error
+func (eb *EventBus) call(handler Handler, event Event) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return handler(event)
+}
+
+// recordFailure stores a handler failure
+func (eb *EventBus) recordFailure(failure SubscriberError) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.failures = append(eb.failures, failure)
+}
@@ -0,0 +1,67 @@
+package state
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPublishAndCloseConcurrently(t *testing.T) {
+	const desks = 8
+	bus := NewEventBus()
+	// a slow handler widens the gap between Publish reading the channels and sending to them
+	bus.Subscribe(func(Event) error {
+		time.Sleep(100 * time.Microsecond)
+		return nil
+	})
+	events := bus.SubscribeChannel(1)
+	go func() {
+		for range events {
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	errs := make([]error, desks)
+	for i := range desks {
+		book := newTestBook("Laut Bercerita", "9786024246945")
+		book.SetEventBus(bus)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if errs[i] = book.Borrow("student-123"); errs[i] != nil {
+					return
+				}
+				if errs[i] = book.Return(); errs[i] != nil {
+					return
+				}
+			}
+		}()
+	}
+	time.Sleep(5 * time.Millisecond)
+	bus.Close()
+	time.Sleep(5 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("desk %d: %v", i, err)
+		}
+	}
+	select {
+	case _, open := <-bus.SubscribeChannel(1):
+		if open {
+			t.Error("channel subscribed after Close received an event")
+		}
+	default:
+		t.Error("channel subscribed after Close is not closed")
+	}
+}
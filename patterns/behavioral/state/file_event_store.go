@@ -0,0 +1,124 @@
+package state
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileEventStore is an event store persisted as a JSON-lines file, one event per line
+// Events are kept in memory for queries and appended to the file as they happen
+type FileEventStore struct {
+	mu     sync.Mutex
+	memory *MemoryEventStore
+	file   *os.File
+	err    error
+}
+
+// OpenFileEventStore loads the events already in the file and appends new events to it
+// The file is created if it does not exist; new loans get IDs after those already in the file
+func OpenFileEventStore(path string) (*FileEventStore, error) {
+	memory, err := ReadEventFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if memory == nil {
+		memory = NewMemoryEventStore()
+	}
+	reserveLoanIDs(memory.All())
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open event file: %w", err)
+	}
+	return &FileEventStore{memory: memory, file: file}, nil
+}
+
+// ReadEventFile loads a JSON-lines event file into an in-memory store for read-only queries
+// The events of every copy must chain, each starting from the state the previous one ended in
+func ReadEventFile(path string) (*MemoryEventStore, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read event file: %w", err)
+	}
+	defer file.Close()
+
+	store := NewMemoryEventStore()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("invalid event on line %d of %s: %w", line, path, err)
+		}
+		if expected := len(store.events) + 1; event.Seq != expected {
+			return nil, fmt.Errorf("event on line %d of %s has sequence %d, expected %d", line, path, event.Seq, expected)
+		}
+		store.events = append(store.events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read event file: %w", err)
+	}
+	if err := checkChain(store.events); err != nil {
+		return nil, fmt.Errorf("inconsistent history in %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// Append stores the event, assigns its sequence number and writes it to the file
+// A failed write does not stop the book, it is reported by Err and Close
+func (fs *FileEventStore) Append(event Event) Event {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	event = fs.memory.Append(event)
+	line, err := json.Marshal(event)
+	if err == nil {
+		_, err = fs.file.Write(append(line, '\n'))
+	}
+	if err != nil && fs.err == nil {
+		fs.err = fmt.Errorf("cannot write event #%d: %w", event.Seq, err)
+	}
+	return event
+}
+
+// ForBook returns the events of every copy of a title in the order they happened
+func (fs *FileEventStore) ForBook(isbn string) []Event {
+	return fs.memory.ForBook(isbn)
+}
+
+// ForItem returns the events of one copy in the order they happened
+func (fs *FileEventStore) ForItem(barcode string) []Event {
+	return fs.memory.ForItem(barcode)
+}
+
+// ForPatron returns the events a patron took part in, as borrower or hold patron
+func (fs *FileEventStore) ForPatron(patronID string) []Event {
+	return fs.memory.ForPatron(patronID)
+}
+
+// All returns every event in the order it happened
+func (fs *FileEventStore) All() []Event {
+	return fs.memory.All()
+}
+
+// Err returns the first error writing an event to the file
+func (fs *FileEventStore) Err() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.err
+}
+
+// Close closes the file and returns the first write error, if any
+func (fs *FileEventStore) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if err := fs.file.Close(); err != nil && fs.err == nil {
+		fs.err = fmt.Errorf("cannot close event file: %w", err)
+	}
+	return fs.err
+}
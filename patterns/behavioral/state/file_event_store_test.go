@@ -0,0 +1,121 @@
+package state
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReopenedFileEventStoreKeepsLoanIDsUnique(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	counted := loanCount.Load()
+	t.Cleanup(func() {
+		if loanCount.Load() < counted {
+			loanCount.Store(counted)
+		}
+	})
+
+	for run := range 3 {
+		// every run stands for a new process appending to the same file
+		loanCount.Store(0)
+		store, err := OpenFileEventStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		book := newTestBook(fmt.Sprintf("Run %d", run), fmt.Sprintf("isbn-run-%d", run))
+		if err := book.SetEventStore(store); err != nil {
+			t.Fatal(err)
+		}
+		if err := book.Borrow("student-123"); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	persisted, err := ReadEventFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for _, event := range persisted.All() {
+		if seen[event.Loan.ID] {
+			t.Errorf("loan ID %s recorded twice", event.Loan.ID)
+		}
+		seen[event.Loan.ID] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("%d loans recorded, want 3", len(seen))
+	}
+}
+
+func TestReopenedFileEventStoreResumesBooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	steps := []func(*Book) error{
+		func(book *Book) error { return book.Borrow("student-123") },
+		(*Book).MarkOverdue,
+		(*Book).Return,
+	}
+	// every step runs in a new process with a fresh book for the same copy
+	for _, step := range steps {
+		store, err := OpenFileEventStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		book := newTestBook("Cantik Itu Luka", "9789792203251")
+		if err := book.SetEventStore(store); err != nil {
+			t.Fatal(err)
+		}
+		if err := step(book); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	persisted, err := ReadEventFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Replay("Cantik Itu Luka", "9789792203251", persisted.All()); err != nil {
+		t.Errorf("replay of the resumed history: %v", err)
+	}
+}
+
+func TestBrokenHistoryIsRejected(t *testing.T) {
+	borrowed := Event{ISBN: "9789792203251", Barcode: "CL-001", From: StateAvailable, To: StateBorrowed,
+		Operation: OpBorrow, Loan: &Loan{ID: "LN-1", BorrowerID: "student-123"}}
+	store := NewMemoryEventStore()
+	store.Append(borrowed)
+	store.Append(Event{ISBN: "9789792203251", Barcode: "CL-001", From: StateBorrowed, To: StateLost, Operation: OpDeclareLost})
+	// the same scenario recorded again, as if the copy had never been lost
+	store.Append(borrowed)
+
+	if _, err := StateAt(store, "CL-001", time.Now()); err == nil {
+		t.Error("StateAt answered from a broken history")
+	}
+	if _, err := OnLoanAt(store, time.Now()); err == nil {
+		t.Error("OnLoanAt answered from a broken history")
+	}
+	if err := NewItem("Cantik Itu Luka", "9789792203251", "CL-001").SetEventStore(store); err == nil {
+		t.Error("a book resumed from a broken history")
+	}
+
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	file, err := OpenFileEventStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range store.All() {
+		file.Append(event)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadEventFile(path); err == nil {
+		t.Error("ReadEventFile loaded a broken history")
+	}
+}
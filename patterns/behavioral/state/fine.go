@@ -0,0 +1,127 @@
+package state
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"library-management-system/internal/money"
+)
+
+// FinePolicy defines how overdue fines are calculated, amounts are in minor currency units
+type FinePolicy struct {
+	DailyRate     int64
+	GraceDays     int
+	MaxFine       int64
+	MaterialRates map[string]int64
+}
+
+// DefaultFinePolicy returns the fine policy used when none is set
+func DefaultFinePolicy() FinePolicy {
+	return FinePolicy{
+		DailyRate: 50,
+		GraceDays: 1,
+		MaxFine:   2000,
+	}
+}
+
+// RateFor returns the daily rate for the material type, falling back to DailyRate
+func (fp FinePolicy) RateFor(materialType string) int64 {
+	if rate, exists := fp.MaterialRates[materialType]; exists {
+		return rate
+	}
+	return fp.DailyRate
+}
+
+// Calculate returns the fine for an item returned daysLate days after its due date
+// Only the days beyond the grace period are charged, and the total is capped at MaxFine
+func (fp FinePolicy) Calculate(materialType string, daysLate int) int64 {
+	chargeable := daysLate - fp.GraceDays
+	if chargeable <= 0 {
+		return 0
+	}
+	amount := int64(chargeable) * fp.RateFor(materialType)
+	if fp.MaxFine > 0 && amount > fp.MaxFine {
+		amount = fp.MaxFine
+	}
+	return amount
+}
+
+// DaysLate returns the number of started days between the due date and the return time
+func DaysLate(dueAt, returnedAt time.Time) int {
+	if !returnedAt.After(dueAt) {
+		return 0
+	}
+	day := 24 * time.Hour
+	return int((returnedAt.Sub(dueAt) + day - 1) / day)
+}
+
+// Fine records a charge to a patron for a loan: an overdue fine, a lost item bill or a reversal of one
+// Reversals have a negative amount and name the charge they cancel in Reverses
+type Fine struct {
+	ID       string
+	Kind     string
+	PatronID string
+	LoanID   string
+	ISBN     string
+	DaysLate int
+	Amount   int64
+	IssuedAt time.Time
+	Reverses string
+}
+
+// String returns a human readable description of the fine
+func (f Fine) String() string {
+	switch f.Kind {
+	case FineReplacement, FineProcessing:
+		return fmt.Sprintf("%s: %s %s fee for loan %s of %s charged to %s",
+			f.ID, money.Format(f.Amount), f.Kind, f.LoanID, f.ISBN, f.PatronID)
+	case FineReversal:
+		return fmt.Sprintf("%s: %s reversing %s for loan %s of %s credited to %s",
+			f.ID, money.Format(f.Amount), f.Reverses, f.LoanID, f.ISBN, f.PatronID)
+	}
+	return fmt.Sprintf("%s: %s for loan %s of %s (%d day(s) late) charged to %s",
+		f.ID, money.Format(f.Amount), f.LoanID, f.ISBN, f.DaysLate, f.PatronID)
+}
+
+// FineLedger stores the fines charged to patrons, safe for concurrent use
+type FineLedger struct {
+	mu    sync.Mutex
+	fines []Fine
+}
+
+// NewFineLedger creates an empty fine ledger
+func NewFineLedger() *FineLedger {
+	return &FineLedger{}
+}
+
+// Record adds a fine to the ledger and assigns its ID
+func (fl *FineLedger) Record(fine Fine) Fine {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+	fine.ID = fmt.Sprintf("FN-%d", len(fl.fines)+1)
+	fl.fines = append(fl.fines, fine)
+	return fine
+}
+
+// ForPatron returns all fines charged to the patron
+func (fl *FineLedger) ForPatron(patronID string) []Fine {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+	fines := make([]Fine, 0)
+	for _, fine := range fl.fines {
+		if fine.PatronID == patronID {
+			fines = append(fines, fine)
+		}
+	}
+	return fines
+}
+
+// Outstanding returns the total amount of fines charged to the patron, net of reversals
+func (fl *FineLedger) Outstanding(patronID string) int64 {
+	var total int64
+	for _, fine := range fl.ForPatron(patronID) {
+		total += fine.Amount
+	}
+	return total
+}
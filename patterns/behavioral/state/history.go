@@ -0,0 +1,170 @@
+package state
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// OpSetState is recorded when a state is set directly with Book.SetState
+const OpSetState Operation = "SetState"
+
+// Event is an immutable record of one state transition of a book
+type Event struct {
+	Seq         int       `json:"seq"`
+	Title       string    `json:"title"`
+	ISBN        string    `json:"isbn"`
+	Barcode     string    `json:"barcode"`
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Operation   Operation `json:"operation"`
+	Actor       string    `json:"actor,omitempty"`
+	PatronID    string    `json:"patron_id,omitempty"`
+	At          time.Time `json:"at"`
+	Loan        *Loan     `json:"loan,omitempty"`
+	Hold        string    `json:"hold,omitempty"`
+	Destination string    `json:"destination,omitempty"`
+}
+
+// String returns a human readable description of the event
+func (e Event) String() string {
+	patron := ""
+	if e.PatronID != "" {
+		patron = ", patron " + e.PatronID
+	}
+	return fmt.Sprintf("#%d %s %s: %s -> %s by %s%s",
+		e.Seq, e.At.Format(time.DateTime), e.Operation, e.From, e.To, e.Actor, patron)
+}
+
+// EventStore keeps the transition history of books
+type EventStore interface {
+	Append(event Event) Event
+	ForBook(isbn string) []Event
+	ForItem(barcode string) []Event
+	ForPatron(patronID string) []Event
+	All() []Event
+}
+
+// MemoryEventStore is an in-memory event store, safe for concurrent use
+type MemoryEventStore struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewMemoryEventStore creates an empty in-memory event store
+func NewMemoryEventStore() *MemoryEventStore {
+	return &MemoryEventStore{}
+}
+
+// Append stores the event and assigns its sequence number
+func (ms *MemoryEventStore) Append(event Event) Event {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	event.Seq = len(ms.events) + 1
+	ms.events = append(ms.events, event)
+	return event
+}
+
+// ForBook returns the events of every copy of a title in the order they happened
+func (ms *MemoryEventStore) ForBook(isbn string) []Event {
+	return ms.filter(func(e Event) bool { return e.ISBN == isbn })
+}
+
+// ForItem returns the events of one copy in the order they happened
+func (ms *MemoryEventStore) ForItem(barcode string) []Event {
+	return ms.filter(func(e Event) bool { return e.Barcode == barcode })
+}
+
+// ForPatron returns the events a patron took part in, as borrower or hold patron
+func (ms *MemoryEventStore) ForPatron(patronID string) []Event {
+	return ms.filter(func(e Event) bool { return e.PatronID == patronID || e.Hold == patronID })
+}
+
+// All returns every event in the order it happened
+func (ms *MemoryEventStore) All() []Event {
+	return ms.filter(func(Event) bool { return true })
+}
+
+// filter returns copies of the events matching the predicate
+func (ms *MemoryEventStore) filter(match func(Event) bool) []Event {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	events := make([]Event, 0)
+	for _, event := range ms.events {
+		if match(event) {
+			events = append(events, event.copy())
+		}
+	}
+	return events
+}
+
+// copy returns the event with its own copy of the loan so callers cannot change stored history
+func (e Event) copy() Event {
+	if e.Loan != nil {
+		loan := *e.Loan
+		e.Loan = &loan
+	}
+	return e
+}
+
+// checkChain verifies that every event of a copy starts from the state the copy's previous event moved it to
+// States set directly with SetState may break the chain on purpose
+func checkChain(events []Event) error {
+	current := make(map[string]string)
+	for _, event := range events {
+		previous, seen := current[event.Barcode]
+		if seen && event.Operation != OpSetState && event.From != previous {
+			return fmt.Errorf("event #%d of %s starts from %s but the copy was %s", event.Seq, event.Barcode, event.From, previous)
+		}
+		current[event.Barcode] = event.To
+	}
+	return nil
+}
+
+// Replay rebuilds a book's current state from its events
+// The copy takes the barcode of the first event
+// Returns an error if the events do not form an unbroken chain starting at Available
+func Replay(title, isbn string, events []Event) (*Book, error) {
+	book := NewBook(title, isbn)
+	if len(events) > 0 && events[0].Barcode != "" {
+		book.barcode = events[0].Barcode
+	}
+	current := book.machine.Definition().Initial
+	for _, event := range events {
+		if event.ISBN != isbn {
+			return nil, fmt.Errorf("event #%d belongs to ISBN '%s', not '%s'", event.Seq, event.ISBN, isbn)
+		}
+		if event.Barcode != "" && event.Barcode != book.barcode {
+			return nil, fmt.Errorf("event #%d belongs to copy '%s', not '%s'", event.Seq, event.Barcode, book.barcode)
+		}
+		if event.Operation != OpSetState && event.From != current {
+			return nil, fmt.Errorf("event #%d starts from %s but the book was %s", event.Seq, event.From, current)
+		}
+		state, err := stateFromEvent(book, event)
+		if err != nil {
+			return nil, err
+		}
+		book.state = state
+		book.hold = event.Hold
+		current = event.To
+	}
+	return book, nil
+}
+
+// stateFromEvent creates the state an event moved the book into
+func stateFromEvent(book *Book, event Event) (BookState, error) {
+	if _, exists := book.machine.Definition().State(event.To); !exists {
+		return BookState{}, fmt.Errorf("event #%d moves to unknown state '%s'", event.Seq, event.To)
+	}
+	return event.state(), nil
+}
+
+// state returns the state the event moved the book into
+func (e Event) state() BookState {
+	state := NewState(e.To)
+	if e.Loan != nil {
+		state = state.withLoan(*e.Loan)
+	}
+	state.Destination = e.Destination
+	return state
+}
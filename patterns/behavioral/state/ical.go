@@ -0,0 +1,219 @@
+package state
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadICalendarFile adds the holidays and closures of an iCalendar file to the calendar
+func (c *Calendar) LoadICalendarFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("cannot open calendar file: %w", err)
+	}
+	defer file.Close()
+	return c.LoadICalendar(file)
+}
+
+// LoadICalendar adds the VEVENTs of an iCalendar stream to the calendar
+// All-day events (DTSTART;VALUE=DATE) become holidays, events with a time become closures;
+// times honour TZID parameters, and yearly all-day events (RRULE:FREQ=YEARLY) repeat as holidays.
+// Other recurrence rules are rejected rather than silently ignored
+func (c *Calendar) LoadICalendar(r io.Reader) error {
+	lines, err := unfoldICalendar(r)
+	if err != nil {
+		return err
+	}
+	var event map[string]icalProperty
+	for number, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			event = make(map[string]icalProperty)
+		case line == "END:VEVENT":
+			if event == nil {
+				return fmt.Errorf("line %d: END:VEVENT without BEGIN:VEVENT", number+1)
+			}
+			if err := c.addICalendarEvent(event); err != nil {
+				return fmt.Errorf("line %d: %w", number+1, err)
+			}
+			event = nil
+		case event != nil:
+			name, property, err := parseICalendarProperty(line)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", number+1, err)
+			}
+			event[name] = property
+		}
+	}
+	if event != nil {
+		return fmt.Errorf("VEVENT is not terminated")
+	}
+	return nil
+}
+
+// icalProperty is the value of a content line with its parameters, e.g. TZID or VALUE
+type icalProperty struct {
+	Value  string
+	Params map[string]string
+}
+
+// parseICalendarProperty splits "NAME;PARAM=value;...:VALUE" into the name and the property
+// Colons and semicolons inside quoted parameter values do not split
+func parseICalendarProperty(line string) (string, icalProperty, error) {
+	quoted := false
+	split := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			split = i
+			break
+		}
+	}
+	if split < 0 {
+		return "", icalProperty{}, fmt.Errorf("malformed property '%s'", line)
+	}
+	head, value := line[:split], line[split+1:]
+	parts := strings.Split(head, ";")
+	property := icalProperty{Value: value, Params: make(map[string]string)}
+	for _, param := range parts[1:] {
+		key, paramValue, found := strings.Cut(param, "=")
+		if !found {
+			return "", icalProperty{}, fmt.Errorf("malformed parameter '%s' of %s", param, parts[0])
+		}
+		property.Params[strings.ToUpper(key)] = strings.Trim(paramValue, `"`)
+	}
+	return strings.ToUpper(parts[0]), property, nil
+}
+
+// addICalendarEvent turns one VEVENT into holidays or a closure
+func (c *Calendar) addICalendarEvent(event map[string]icalProperty) error {
+	summary := event["SUMMARY"].Value
+	dtstart, exists := event["DTSTART"]
+	if !exists {
+		return fmt.Errorf("event '%s' has no DTSTART", summary)
+	}
+	rule, recurring := event["RRULE"]
+	if dtstart.Params["VALUE"] == "DATE" || len(dtstart.Value) == len("20060102") {
+		start, err := time.ParseInLocation("20060102", dtstart.Value, c.location)
+		if err != nil {
+			return fmt.Errorf("invalid DTSTART of '%s': %w", summary, err)
+		}
+		end := start.AddDate(0, 0, 1)
+		if dtend, exists := event["DTEND"]; exists {
+			if end, err = time.ParseInLocation("20060102", dtend.Value, c.location); err != nil {
+				return fmt.Errorf("invalid DTEND of '%s': %w", summary, err)
+			}
+		}
+		if recurring {
+			return c.addYearlyHolidays(start, end, rule.Value, summary)
+		}
+		for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+			c.AddHoliday(day, summary)
+		}
+		return nil
+	}
+
+	if recurring {
+		return fmt.Errorf("unsupported RRULE on timed event '%s', only all-day events may recur", summary)
+	}
+	start, err := c.parseICalendarTime(dtstart)
+	if err != nil {
+		return fmt.Errorf("invalid DTSTART of '%s': %w", summary, err)
+	}
+	end, err := c.parseICalendarTime(event["DTEND"])
+	if err != nil {
+		return fmt.Errorf("invalid DTEND of '%s': %w", summary, err)
+	}
+	return c.AddClosure(start, end, summary)
+}
+
+// addYearlyHolidays adds an all-day event recurring with RRULE:FREQ=YEARLY
+// COUNT and UNTIL bound the recurrence, without them the holiday repeats every year
+func (c *Calendar) addYearlyHolidays(start, end time.Time, rule, summary string) error {
+	count, until := 0, time.Time{}
+	for _, part := range strings.Split(rule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			if strings.ToUpper(value) != "YEARLY" {
+				return fmt.Errorf("unsupported RRULE '%s' of '%s', only FREQ=YEARLY is supported", rule, summary)
+			}
+		case "INTERVAL":
+			if value != "1" {
+				return fmt.Errorf("unsupported RRULE '%s' of '%s', INTERVAL must be 1", rule, summary)
+			}
+		case "COUNT":
+			if count, err = strconv.Atoi(value); err != nil || count <= 0 {
+				return fmt.Errorf("invalid COUNT in RRULE of '%s'", summary)
+			}
+		case "UNTIL":
+			if until, err = time.ParseInLocation("20060102", value[:min(len(value), 8)], c.location); err != nil {
+				return fmt.Errorf("invalid UNTIL in RRULE of '%s': %w", summary, err)
+			}
+		default:
+			return fmt.Errorf("unsupported RRULE '%s' of '%s', %s is not supported", rule, summary, key)
+		}
+	}
+	if !strings.Contains(strings.ToUpper(rule), "FREQ=") {
+		return fmt.Errorf("RRULE of '%s' has no FREQ", summary)
+	}
+	span := int(end.Sub(start).Hours()/24 + 0.5)
+	if count == 0 && until.IsZero() {
+		for offset := 0; offset < span; offset++ {
+			c.AddAnnualHoliday(start.AddDate(0, 0, offset), summary)
+		}
+		return nil
+	}
+	for year := 0; count == 0 || year < count; year++ {
+		occurrence := start.AddDate(year, 0, 0)
+		if !until.IsZero() && occurrence.After(until) {
+			break
+		}
+		for offset := 0; offset < span; offset++ {
+			c.AddHoliday(occurrence.AddDate(0, 0, offset), summary)
+		}
+	}
+	return nil
+}
+
+// parseICalendarTime parses a UTC (trailing Z), TZID-qualified or floating iCalendar date-time
+func (c *Calendar) parseICalendarTime(property icalProperty) (time.Time, error) {
+	value := property.Value
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse("20060102T150405Z", value)
+	}
+	location := c.location
+	if tzid := property.Params["TZID"]; tzid != "" {
+		loaded, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unknown TZID '%s': %w", tzid, err)
+		}
+		location = loaded
+	}
+	return time.ParseInLocation("20060102T150405", value, location)
+}
+
+// unfoldICalendar reads the content lines, joining folded continuation lines
+func unfoldICalendar(r io.Reader) ([]string, error) {
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read calendar: %w", err)
+	}
+	return lines, nil
+}
@@ -0,0 +1,77 @@
+package state
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadICalendarHonoursTZID(t *testing.T) {
+	calendar := NewCalendar("Pusat", time.UTC)
+	err := calendar.LoadICalendar(strings.NewReader(strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"DTSTART;TZID=Asia/Jakarta:20250204T160000",
+		"DTEND;TZID=\"Asia/Jakarta\":20250205T000000",
+		"SUMMARY:Stock opname",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 16:00-24:00 in Jakarta is 09:00-17:00 UTC, the whole opening hours of the day
+	if calendar.IsOpenDay(time.Date(2025, time.February, 4, 12, 0, 0, 0, time.UTC)) {
+		t.Error("branch is open during a closure given in Asia/Jakarta")
+	}
+}
+
+func TestLoadICalendarYearlyHolidays(t *testing.T) {
+	calendar := NewCalendar("Pusat", time.UTC)
+	err := calendar.LoadICalendar(strings.NewReader(strings.Join([]string{
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20250817",
+		"RRULE:FREQ=YEARLY",
+		"SUMMARY:Hari Kemerdekaan",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20250101",
+		"RRULE:FREQ=YEARLY;COUNT=2",
+		"SUMMARY:Tahun Baru",
+		"END:VEVENT",
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		date    time.Time
+		holiday bool
+	}{
+		{time.Date(2024, time.August, 17, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, time.August, 17, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2031, time.August, 17, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, test := range tests {
+		if _, holiday := calendar.Holiday(test.date); holiday != test.holiday {
+			t.Errorf("Holiday(%s) = %t, want %t", test.date.Format(time.DateOnly), holiday, test.holiday)
+		}
+	}
+}
+
+func TestLoadICalendarRejectsUnsupportedRules(t *testing.T) {
+	for _, rule := range []string{"FREQ=WEEKLY", "FREQ=YEARLY;BYMONTH=8", "FREQ=YEARLY;INTERVAL=2"} {
+		calendar := NewCalendar("Pusat", time.UTC)
+		err := calendar.LoadICalendar(strings.NewReader(strings.Join([]string{
+			"BEGIN:VEVENT",
+			"DTSTART;VALUE=DATE:20250817",
+			"RRULE:" + rule,
+			"SUMMARY:Hari Kemerdekaan",
+			"END:VEVENT",
+		}, "\n")))
+		if err == nil {
+			t.Errorf("RRULE:%s was accepted", rule)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package state
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultLoanPeriod is how long a book is lent when no other period is set
+const DefaultLoanPeriod = 14 * 24 * time.Hour
+
+// Loan records who borrowed a book and when it has to be back
+type Loan struct {
+	ID           string    `json:"id"`
+	BorrowerID   string    `json:"borrower_id"`
+	CheckedOutAt time.Time `json:"checked_out_at"`
+	DueAt        time.Time `json:"due_at"`
+	Renewals     int       `json:"renewals,omitempty"`
+	RecalledAt   time.Time `json:"recalled_at,omitzero"`
+	RecalledBy   string    `json:"recalled_by,omitempty"`
+}
+
+// IsOverdue checks if the loan is past its due date at the given time
+func (l Loan) IsOverdue(now time.Time) bool {
+	return now.After(l.DueAt)
+}
+
+// IsRecalled checks if the loan has been recalled
+func (l Loan) IsRecalled() bool {
+	return !l.RecalledAt.IsZero()
+}
+
+// RenewalPolicy defines how often and by how much a loan can be extended
+type RenewalPolicy struct {
+	MaxRenewals int
+	// Period is the extension per renewal, zero means the book's loan period
+	Period time.Duration
+	// FromDueDate extends from the current due date instead of from the renewal time
+	FromDueDate bool
+}
+
+// DefaultRenewalPolicy returns the renewal policy used when none is set
+func DefaultRenewalPolicy() RenewalPolicy {
+	return RenewalPolicy{MaxRenewals: 2}
+}
+
+// NextDueDate returns the due date after one renewal, a renewal never shortens a loan
+func (rp RenewalPolicy) NextDueDate(dueAt, now time.Time, loanPeriod time.Duration) time.Time {
+	period := rp.Period
+	if period == 0 {
+		period = loanPeriod
+	}
+	start := now
+	if rp.FromDueDate {
+		start = dueAt
+	}
+	next := start.Add(period)
+	if next.Before(dueAt) {
+		return dueAt
+	}
+	return next
+}
+
+// loanCount numbers loans across all books, which borrow concurrently under their own locks
+var loanCount atomic.Int64
+
+// generateLoanID generates a unique ID for a loan using counter
+func generateLoanID() string {
+	return fmt.Sprintf("LN-%d", loanCount.Add(1))
+}
+
+// reserveLoanIDs moves the loan counter past the loan IDs already used in the events,
+// so loans recorded by an earlier run keep their IDs unique
+func reserveLoanIDs(events []Event) {
+	for _, event := range events {
+		var number int64
+		if event.Loan == nil {
+			continue
+		}
+		if _, err := fmt.Sscanf(event.Loan.ID, "LN-%d", &number); err != nil {
+			continue
+		}
+		for current := loanCount.Load(); number > current; current = loanCount.Load() {
+			if loanCount.CompareAndSwap(current, number) {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package state
+
+// Fine kinds recorded in the fine ledger
+const (
+	FineOverdue     = "overdue"
+	FineReplacement = "replacement"
+	FineProcessing  = "processing"
+	FineReversal    = "reversal"
+)
+
+// LostItemPolicy defines when an overdue item is declared lost and what the patron is billed
+// ReplacementCost is used for books without a replacement cost of their own
+type LostItemPolicy struct {
+	DaysOverdue     int
+	ReplacementCost int64
+	ProcessingFee   int64
+}
+
+// DefaultLostItemPolicy returns the lost item policy used when none is set
+func DefaultLostItemPolicy() LostItemPolicy {
+	return LostItemPolicy{
+		DaysOverdue:     60,
+		ReplacementCost: 2500,
+		ProcessingFee:   500,
+	}
+}
+
+// billLoss charges the borrower the replacement cost and processing fee of a lost book
+func (b *Book) billLoss(loan Loan) []Fine {
+	cost := b.replacementCost
+	if cost == 0 {
+		cost = b.lostPolicy.ReplacementCost
+	}
+	now := b.clock.Now()
+	bills := make([]Fine, 0, 2)
+	for _, bill := range []Fine{
+		{Kind: FineReplacement, Amount: cost},
+		{Kind: FineProcessing, Amount: b.lostPolicy.ProcessingFee},
+	} {
+		if bill.Amount == 0 {
+			continue
+		}
+		bill.PatronID = loan.BorrowerID
+		bill.LoanID = loan.ID
+		bill.ISBN = b.isbn
+		bill.IssuedAt = now
+		bills = append(bills, b.fineLedger.Record(bill))
+	}
+	return bills
+}
+
+// reverseLoss cancels the replacement and processing bills of a lost book that was returned
+func (b *Book) reverseLoss(loan Loan) []Fine {
+	now := b.clock.Now()
+	reversals := make([]Fine, 0)
+	for _, fine := range b.fineLedger.ForPatron(loan.BorrowerID) {
+		if fine.LoanID != loan.ID || (fine.Kind != FineReplacement && fine.Kind != FineProcessing) {
+			continue
+		}
+		reversals = append(reversals, b.fineLedger.Record(Fine{
+			Kind:     FineReversal,
+			PatronID: fine.PatronID,
+			LoanID:   fine.LoanID,
+			ISBN:     fine.ISBN,
+			Amount:   -fine.Amount,
+			IssuedAt: now,
+			Reverses: fine.ID,
+		}))
+	}
+	return reversals
+}
@@ -0,0 +1,396 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// StateDef declares a state of the machine
+// Label completes "book is ..." in messages; states carrying a loan keep the loan of the state they come from
+type StateDef struct {
+	Name        string `json:"name"`
+	Label       string `json:"label"`
+	CarriesLoan bool   `json:"carries_loan,omitempty"`
+}
+
+// OperationDef declares an operation, Verb completes "cannot ..." in error messages
+type OperationDef struct {
+	Name Operation `json:"name"`
+	Verb string    `json:"verb"`
+}
+
+// TransitionDef declares one edge of the machine
+// Guards are checked in order, the first edge of an operation whose guards all pass is taken;
+// actions prepare the next state before it is committed and may still reject the transition,
+// so an action that cannot be undone (such as counting a loan) must come last;
+// effects run after the commit and cannot fail
+type TransitionDef struct {
+	From      string    `json:"from"`
+	Operation Operation `json:"operation"`
+	To        string    `json:"to"`
+	Guards    []string  `json:"guards,omitempty"`
+	Actions   []string  `json:"actions,omitempty"`
+	Effects   []string  `json:"effects,omitempty"`
+}
+
+// Definition describes a state machine as data
+type Definition struct {
+	Initial     string          `json:"initial"`
+	States      []StateDef      `json:"states"`
+	Operations  []OperationDef  `json:"operations"`
+	Transitions []TransitionDef `json:"transitions"`
+}
+
+// ParseDefinition reads a state machine definition from JSON
+// Unknown keys are rejected, so a misspelled "guards" cannot silently drop a transition's guards
+func ParseDefinition(data []byte) (Definition, error) {
+	var definition Definition
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&definition); err != nil {
+		return Definition{}, fmt.Errorf("invalid state machine definition: %w", err)
+	}
+	if decoder.More() {
+		return Definition{}, fmt.Errorf("invalid state machine definition: unexpected data after the definition")
+	}
+	return definition, nil
+}
+
+// FormatDefinition writes a state machine definition as indented JSON, the format read by ParseDefinition
+func FormatDefinition(definition Definition) ([]byte, error) {
+	data, err := json.MarshalIndent(definition, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("cannot format state machine definition: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// LoadDefinitionFile reads a state machine definition from a JSON file
+func LoadDefinitionFile(path string) (Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Definition{}, fmt.Errorf("cannot read state machine definition: %w", err)
+	}
+	return ParseDefinition(data)
+}
+
+// State returns the declaration of the named state
+func (d Definition) State(name string) (StateDef, bool) {
+	for _, state := range d.States {
+		if state.Name == name {
+			return state, true
+		}
+	}
+	return StateDef{}, false
+}
+
+// StateNames returns the state names in declaration order
+func (d Definition) StateNames() []string {
+	names := make([]string, len(d.States))
+	for i, state := range d.States {
+		names[i] = state.Name
+	}
+	return names
+}
+
+// AllowedOperations returns the operations allowed from a state, in declaration order
+func (d Definition) AllowedOperations(from string) []Operation {
+	operations := make([]Operation, 0)
+	seen := make(map[Operation]bool)
+	for _, transition := range d.Transitions {
+		if transition.From == from && !seen[transition.Operation] {
+			seen[transition.Operation] = true
+			operations = append(operations, transition.Operation)
+		}
+	}
+	return operations
+}
+
+// TerminalStates returns the states without outgoing transitions, in declaration order
+func (d Definition) TerminalStates() []string {
+	terminal := make([]string, 0)
+	for _, name := range d.StateNames() {
+		if len(d.AllowedOperations(name)) == 0 {
+			terminal = append(terminal, name)
+		}
+	}
+	return terminal
+}
+
+// DefinitionError lists every problem found while validating a definition
+type DefinitionError struct {
+	Problems []string
+}
+
+// Error returns all problems
+func (de *DefinitionError) Error() string {
+	return "invalid state machine definition: " + strings.Join(de.Problems, "; ")
+}
+
+// Validate checks the definition against the behaviors it references
+// It reports undeclared names, unreachable states, shadowed transitions,
+// a missing terminal state and states from which no terminal state can be reached
+func (d Definition) Validate(behaviors Behaviors) error {
+	problems := make([]string, 0)
+	states := make(map[string]bool)
+	for _, state := range d.States {
+		if states[state.Name] {
+			problems = append(problems, fmt.Sprintf("state %s is declared twice", state.Name))
+		}
+		states[state.Name] = true
+	}
+	operations := make(map[Operation]bool)
+	for _, operation := range d.Operations {
+		if operations[operation.Name] {
+			problems = append(problems, fmt.Sprintf("operation %s is declared twice", operation.Name))
+		}
+		operations[operation.Name] = true
+	}
+	if !states[d.Initial] {
+		problems = append(problems, fmt.Sprintf("initial state '%s' is not declared", d.Initial))
+	}
+
+	unguarded := make(map[string]bool)
+	for _, transition := range d.Transitions {
+		edge := fmt.Sprintf("%s -%s-> %s", transition.From, transition.Operation, transition.To)
+		if !states[transition.From] {
+			problems = append(problems, fmt.Sprintf("%s starts from undeclared state %s", edge, transition.From))
+		}
+		if !states[transition.To] {
+			problems = append(problems, fmt.Sprintf("%s leads to undeclared state %s", edge, transition.To))
+		}
+		if !operations[transition.Operation] {
+			problems = append(problems, fmt.Sprintf("%s uses undeclared operation %s", edge, transition.Operation))
+		}
+		for _, name := range transition.Guards {
+			if _, exists := behaviors.Guards[name]; !exists {
+				problems = append(problems, fmt.Sprintf("%s uses unknown guard '%s'", edge, name))
+			}
+		}
+		for _, name := range transition.Actions {
+			if _, exists := behaviors.Actions[name]; !exists {
+				problems = append(problems, fmt.Sprintf("%s uses unknown action '%s'", edge, name))
+			}
+		}
+		for _, name := range transition.Effects {
+			if _, exists := behaviors.Effects[name]; !exists {
+				problems = append(problems, fmt.Sprintf("%s uses unknown effect '%s'", edge, name))
+			}
+		}
+		key := transition.From + "/" + string(transition.Operation)
+		if unguarded[key] {
+			problems = append(problems, fmt.Sprintf("%s is never taken, an earlier %s transition from %s has no guards",
+				edge, transition.Operation, transition.From))
+		}
+		if len(transition.Guards) == 0 {
+			unguarded[key] = true
+		}
+	}
+
+	reachable := d.reachableFrom([]string{d.Initial}, func(t TransitionDef) (string, string) { return t.From, t.To })
+	for _, name := range d.StateNames() {
+		if !reachable[name] {
+			problems = append(problems, fmt.Sprintf("state %s is unreachable from %s", name, d.Initial))
+		}
+	}
+	terminal := d.TerminalStates()
+	if len(terminal) == 0 {
+		problems = append(problems, "no terminal state, every state has outgoing transitions")
+	} else {
+		reachesEnd := d.reachableFrom(terminal, func(t TransitionDef) (string, string) { return t.To, t.From })
+		for _, name := range d.StateNames() {
+			if !reachesEnd[name] {
+				problems = append(problems, fmt.Sprintf("state %s cannot reach a terminal state", name))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &DefinitionError{Problems: problems}
+	}
+	return nil
+}
+
+// reachableFrom walks the transitions from the start states along the direction given by edge
+func (d Definition) reachableFrom(start []string, edge func(TransitionDef) (string, string)) map[string]bool {
+	reached := make(map[string]bool)
+	queue := append([]string(nil), start...)
+	for _, name := range start {
+		reached[name] = true
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, transition := range d.Transitions {
+			from, to := edge(transition)
+			if from == current && !reached[to] {
+				reached[to] = true
+				queue = append(queue, to)
+			}
+		}
+	}
+	return reached
+}
+
+// Machine runs a validated definition with the guards, actions and effects it references
+type Machine struct {
+	definition Definition
+	behaviors  Behaviors
+}
+
+// NewMachine validates the definition and creates a machine for it
+func NewMachine(definition Definition, behaviors Behaviors) (*Machine, error) {
+	if err := definition.Validate(behaviors); err != nil {
+		return nil, err
+	}
+	return &Machine{definition: definition, behaviors: behaviors}, nil
+}
+
+// LoadMachineFile loads a definition from a JSON file and creates a machine with the default behaviors
+func LoadMachineFile(path string) (*Machine, error) {
+	definition, err := LoadDefinitionFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewMachine(definition, DefaultBehaviors())
+}
+
+var defaultMachine *Machine
+
+// DefaultMachine returns the machine of DefaultDefinition used by new books
+func DefaultMachine() *Machine {
+	return defaultMachine
+}
+
+func init() {
+	machine, err := NewMachine(DefaultDefinition(), DefaultBehaviors())
+	if err != nil {
+		panic(err)
+	}
+	defaultMachine = machine
+}
+
+// Definition returns the definition the machine runs
+func (m *Machine) Definition() Definition {
+	return m.definition
+}
+
+// Transition is one firing of an operation, passed to guards, actions and effects
+// Actions fill in To and Hold, which are committed to the book once all actions succeeded
+type Transition struct {
+	Book        *Book
+	Operation   Operation
+	PatronID    string
+	Destination string
+	From        BookState
+	To          BookState
+	Hold        string
+}
+
+// TransitionGuard allows a transition or returns the reason it is not allowed
+type TransitionGuard func(t *Transition) error
+
+// TransitionAction prepares the next state, an error rejects the transition
+type TransitionAction func(t *Transition) error
+
+// TransitionEffect runs after a transition has been committed
+type TransitionEffect func(t *Transition)
+
+// Behaviors holds the named guards, actions and effects a definition can reference
+type Behaviors struct {
+	Guards  map[string]TransitionGuard
+	Actions map[string]TransitionAction
+	Effects map[string]TransitionEffect
+}
+
+// fire runs an operation on a book, must be called with the book lock held
+func (m *Machine) fire(b *Book, request Transition) error {
+	from := b.state
+	var rejected error
+	for _, definition := range m.definition.Transitions {
+		if definition.From != from.Name || definition.Operation != request.Operation {
+			continue
+		}
+		t := request
+		t.Book = b
+		t.From = from
+		t.To = NewState(definition.To)
+		t.Hold = b.hold
+		if state, _ := m.definition.State(definition.To); state.CarriesLoan && from.Loan != nil {
+			t.To = t.To.withLoan(*from.Loan)
+		}
+		if err := m.checkGuards(definition, &t); err != nil {
+			if rejected == nil {
+				rejected = err
+			}
+			continue
+		}
+		for _, name := range definition.Actions {
+			if err := m.behaviors.Actions[name](&t); err != nil {
+				return err
+			}
+		}
+		b.hold = t.Hold
+		b.moveTo(t.To, t.Operation)
+		if from.Loan != nil && t.To.Loan == nil {
+			b.endLoanFor(from.Loan.BorrowerID)
+		}
+		if t.To.Name != from.Name {
+			b.log("book is now "+m.label(t.To.Name), "from", from.Name, "to", t.To.Name, "operation", t.Operation)
+		}
+		for _, name := range definition.Effects {
+			m.behaviors.Effects[name](&t)
+		}
+		return nil
+	}
+	if rejected != nil {
+		return rejected
+	}
+	return invalidTransition(from.Name, request.Operation, m.disallowed(from.Name, request.Operation))
+}
+
+// checkGuards returns the error of the first guard of a transition that fails
+func (m *Machine) checkGuards(definition TransitionDef, t *Transition) error {
+	for _, name := range definition.Guards {
+		if err := m.behaviors.Guards[name](t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// disallowed generates the message for an operation the state has no transition for
+func (m *Machine) disallowed(stateName string, operation Operation) string {
+	verb := strings.ToLower(string(operation))
+	for _, declared := range m.definition.Operations {
+		if declared.Name == operation && declared.Verb != "" {
+			verb = declared.Verb
+		}
+	}
+	message := fmt.Sprintf("cannot %s: book is %s", verb, m.label(stateName))
+	allowed := m.definition.AllowedOperations(stateName)
+	if len(allowed) == 0 {
+		return message
+	}
+	names := make([]string, len(allowed))
+	for i, operation := range allowed {
+		names[i] = string(operation)
+	}
+	return fmt.Sprintf("%s (allowed: %s)", message, strings.Join(names, ", "))
+}
+
+// label returns the label of a state, falling back to its name
+func (m *Machine) label(stateName string) string {
+	if state, exists := m.definition.State(stateName); exists && state.Label != "" {
+		return state.Label
+	}
+	return stateName
+}
+
+// rejectTransition creates the error a guard or action returns to reject the transition
+func rejectTransition(t *Transition, reason string) error {
+	return invalidTransition(t.From.Name, t.Operation, reason)
+}
@@ -0,0 +1,55 @@
+package state
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDefinitionFileMatchesDefaultDefinition(t *testing.T) {
+	const path = "../../../config/book_state_machine.json"
+	file, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := FormatDefinition(DefaultDefinition())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(file, want) {
+		t.Errorf("%s differs from DefaultDefinition, run go generate ./...", path)
+	}
+}
+
+func TestParseDefinitionRejectsUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		wantErr    string
+	}{
+		{
+			name:       "misspelled guards",
+			definition: `{"initial": "available", "transitions": [{"from": "available", "operation": "borrow", "to": "borrowed", "gaurds": ["not_held"]}]}`,
+			wantErr:    `unknown field "gaurds"`,
+		},
+		{
+			name:       "unknown top-level key",
+			definition: `{"initial": "available", "state": []}`,
+			wantErr:    `unknown field "state"`,
+		},
+		{
+			name:       "trailing data",
+			definition: `{"initial": "available"} {"initial": "borrowed"}`,
+			wantErr:    "unexpected data after the definition",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseDefinition([]byte(test.definition))
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("got %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package state
+
+// Operation names an action that may move a book between states
+type Operation string
+
+// Operations supported by every book state
+const (
+	OpBorrow        Operation = "Borrow"
+	OpReturn        Operation = "Return"
+	OpMarkOverdue   Operation = "MarkOverdue"
+	OpRenew         Operation = "Renew"
+	OpRecall        Operation = "Recall"
+	OpPlaceHold     Operation = "PlaceHold"
+	OpCancelHold    Operation = "CancelHold"
+	OpShip          Operation = "Ship"
+	OpReceive       Operation = "Receive"
+	OpDeclareLost   Operation = "DeclareLost"
+	OpWithdraw      Operation = "Withdraw"
+	OpClaimReturned Operation = "ClaimReturned"
+)
+
+// State names returned by GetStateName
+const (
+	StateAvailable      = "Available"
+	StateBorrowed       = "Borrowed"
+	StateOverdue        = "Overdue"
+	StateReserved       = "Reserved"
+	StateOnHoldShelf    = "OnHoldShelf"
+	StateInTransit      = "InTransit"
+	StateLost           = "Lost"
+	StateMissing        = "Missing"
+	StateWithdrawn      = "Withdrawn"
+	StateClaimsReturned = "ClaimsReturned"
+)
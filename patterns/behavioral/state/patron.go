@@ -0,0 +1,101 @@
+package state
+
+import (
+	"sync"
+	"time"
+)
+
+// Patron is a library member as seen by the borrowing guards
+type Patron struct {
+	ID            string
+	Name          string
+	Category      string
+	Active        bool
+	CardExpiresAt time.Time
+	LoansOut      int
+	FinesOwed     int64
+}
+
+// PatronDirectory looks up patrons by ID
+type PatronDirectory interface {
+	FindPatron(id string) (Patron, bool)
+}
+
+// PatronRegistry is an in-memory patron directory, safe for concurrent use
+// It counts the loans of every book that uses it for eligibility and reads fines from a fine ledger
+type PatronRegistry struct {
+	mu      sync.Mutex
+	patrons map[string]Patron
+	ledger  *FineLedger
+}
+
+// NewPatronRegistry creates an empty patron registry
+func NewPatronRegistry() *PatronRegistry {
+	return &PatronRegistry{patrons: make(map[string]Patron)}
+}
+
+// Register adds or replaces a patron
+func (pr *PatronRegistry) Register(patron Patron) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.patrons[patron.ID] = patron
+}
+
+// SetFineLedger makes FindPatron add the fines outstanding in the ledger to the registered fines
+func (pr *PatronRegistry) SetFineLedger(ledger *FineLedger) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.ledger = ledger
+}
+
+// FindPatron returns the patron with the given ID
+func (pr *PatronRegistry) FindPatron(id string) (Patron, bool) {
+	pr.mu.Lock()
+	patron, exists := pr.patrons[id]
+	ledger := pr.ledger
+	pr.mu.Unlock()
+	if exists && ledger != nil {
+		patron.FinesOwed += ledger.Outstanding(id)
+	}
+	return patron, exists
+}
+
+// LoanTracker is a patron directory that keeps the loan counts itself
+// Books check the patron and count the loan in one step, so concurrent loans cannot both pass the limit
+type LoanTracker interface {
+	PatronDirectory
+	// StartLoan runs check on the patron and counts a loan if it passes, atomically
+	StartLoan(patronID string, check func(Patron) error) error
+	// EndLoan counts a loan of the patron as returned
+	EndLoan(patronID string)
+}
+
+// StartLoan checks the patron under the registry lock and counts the loan if the check passes
+func (pr *PatronRegistry) StartLoan(patronID string, check func(Patron) error) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	patron, exists := pr.patrons[patronID]
+	if !exists {
+		return &EligibilityError{PatronID: patronID, Reasons: []string{"patron is not registered"}}
+	}
+	registered := patron
+	if pr.ledger != nil {
+		patron.FinesOwed += pr.ledger.Outstanding(patronID)
+	}
+	if err := check(patron); err != nil {
+		return err
+	}
+	registered.LoansOut++
+	pr.patrons[patronID] = registered
+	return nil
+}
+
+// EndLoan counts a loan of the patron as returned
+func (pr *PatronRegistry) EndLoan(patronID string) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if patron, exists := pr.patrons[patronID]; exists && patron.LoansOut > 0 {
+		patron.LoansOut--
+		pr.patrons[patronID] = patron
+	}
+}
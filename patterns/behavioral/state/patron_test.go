@@ -0,0 +1,64 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentLoansRespectLoanLimit(t *testing.T) {
+	const books = 16
+	patrons := NewPatronRegistry()
+	patrons.Register(Patron{ID: "student-123", Category: "student", Active: true, LoansOut: 2})
+	policy := DefaultEligibilityPolicy()
+
+	var wg sync.WaitGroup
+	errs := make([]error, books)
+	for i := range books {
+		book := newTestBook(fmt.Sprintf("Book %d", i), fmt.Sprintf("isbn-%d", i))
+		book.SetEligibility(patrons, policy)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = book.Borrow("student-123")
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, ErrNotEligible):
+			t.Errorf("book %d: got %v, want not eligible", i, err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d loans started, want 1 (limit 3 with 2 out)", succeeded)
+	}
+	if patron, _ := patrons.FindPatron("student-123"); patron.LoansOut != 3 {
+		t.Errorf("loans out = %d, want 3", patron.LoansOut)
+	}
+}
+
+func TestReturnEndsLoanWithRegistry(t *testing.T) {
+	patrons := NewPatronRegistry()
+	patrons.Register(Patron{ID: "staff-7", Category: "staff", Active: true})
+	book := newTestBook("Clean Code", "9780132350884")
+	book.SetEligibility(patrons, DefaultEligibilityPolicy())
+
+	if err := book.Borrow("staff-7"); err != nil {
+		t.Fatal(err)
+	}
+	if patron, _ := patrons.FindPatron("staff-7"); patron.LoansOut != 1 {
+		t.Fatalf("loans out after borrow = %d, want 1", patron.LoansOut)
+	}
+	if err := book.Return(); err != nil {
+		t.Fatal(err)
+	}
+	if patron, _ := patrons.FindPatron("staff-7"); patron.LoansOut != 0 {
+		t.Errorf("loans out after return = %d, want 0", patron.LoansOut)
+	}
+}
@@ -0,0 +1,105 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrNoHistory is returned when the history has no events for an item
+var ErrNoHistory = errors.New("no history recorded")
+
+// StateAt rebuilds the state a copy was in at the given time from the recorded history
+// The copy is looked up by barcode, or by ISBN for titles with a single copy;
+// before its first recorded transition a copy is in the state that transition started from;
+// a history whose events do not chain is rejected rather than answered from
+func StateAt(store EventStore, id string, at time.Time) (BookState, error) {
+	events := store.ForItem(id)
+	if len(events) == 0 {
+		events = store.ForBook(id)
+		if copies := barcodes(events); len(copies) > 1 {
+			return BookState{}, fmt.Errorf("ISBN '%s' has %d copies, use a barcode (%v)", id, len(copies), copies)
+		}
+	}
+	if len(events) == 0 {
+		return BookState{}, fmt.Errorf("cannot rebuild state of '%s': %w", id, ErrNoHistory)
+	}
+	if err := checkChain(events); err != nil {
+		return BookState{}, fmt.Errorf("cannot rebuild state of '%s': %w", id, err)
+	}
+	state := NewState(events[0].From)
+	for _, event := range events {
+		if event.At.After(at) {
+			break
+		}
+		state = event.state()
+	}
+	return state, nil
+}
+
+// StateAt returns the state the book was in at the given time according to its history
+func (b *Book) StateAt(at time.Time) (BookState, error) {
+	return StateAt(b.history, b.barcode, at)
+}
+
+// barcodes returns the distinct barcodes of the events in order of appearance
+func barcodes(events []Event) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0)
+	for _, event := range events {
+		if !seen[event.Barcode] {
+			seen[event.Barcode] = true
+			result = append(result, event.Barcode)
+		}
+	}
+	return result
+}
+
+// LoanSnapshot is a copy that carried a loan at a given time
+type LoanSnapshot struct {
+	Title   string
+	ISBN    string
+	Barcode string
+	State   string
+	Loan    Loan
+}
+
+// String returns a human readable description of the snapshot
+func (ls LoanSnapshot) String() string {
+	return fmt.Sprintf("%s '%s' (%s): %s, borrowed by %s since %s, due %s",
+		ls.Barcode, ls.Title, ls.ISBN, ls.State, ls.Loan.BorrowerID,
+		ls.Loan.CheckedOutAt.Format(time.DateTime), ls.Loan.DueAt.Format(time.DateTime))
+}
+
+// OnLoanAt reports every copy that carried a loan at the given time, ordered by barcode
+// Overdue, lost and claimed-returned copies still carry their loan and are reported with their state
+func OnLoanAt(store EventStore, at time.Time) ([]LoanSnapshot, error) {
+	events := store.All()
+	if err := checkChain(events); err != nil {
+		return nil, fmt.Errorf("cannot report loans: %w", err)
+	}
+	latest := make(map[string]Event)
+	for _, event := range events {
+		if !event.At.After(at) {
+			latest[event.Barcode] = event
+		}
+	}
+	snapshots := make([]LoanSnapshot, 0)
+	for barcode, event := range latest {
+		if event.Loan == nil {
+			continue
+		}
+		snapshots = append(snapshots, LoanSnapshot{
+			Title:   event.Title,
+			ISBN:    event.ISBN,
+			Barcode: barcode,
+			State:   event.To,
+			Loan:    *event.Loan,
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Barcode < snapshots[j].Barcode
+	})
+	return snapshots, nil
+}
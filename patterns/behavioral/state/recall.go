@@ -0,0 +1,103 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecallPolicy defines how far a recall shortens a loan and the fine rate of recalled loans
+// A recalled loan is due GuaranteedPeriod after checkout, but never sooner than NoticePeriod after the recall
+type RecallPolicy struct {
+	GuaranteedPeriod time.Duration
+	NoticePeriod     time.Duration
+	DailyRate        int64
+}
+
+// DefaultRecallPolicy returns the recall policy used when none is set
+func DefaultRecallPolicy() RecallPolicy {
+	return RecallPolicy{
+		GuaranteedPeriod: 7 * 24 * time.Hour,
+		NoticePeriod:     3 * 24 * time.Hour,
+		DailyRate:        200,
+	}
+}
+
+// RecalledDueDate returns the due date of the loan after a recall at now, a recall never extends a loan
+func (rp RecallPolicy) RecalledDueDate(loan Loan, now time.Time) time.Time {
+	dueAt := loan.CheckedOutAt.Add(rp.GuaranteedPeriod)
+	if earliest := now.Add(rp.NoticePeriod); dueAt.Before(earliest) {
+		dueAt = earliest
+	}
+	if dueAt.After(loan.DueAt) {
+		return loan.DueAt
+	}
+	return dueAt
+}
+
+// recallLoan returns the loan shortened by the book's recall policy
+func (b *Book) recallLoan(loan Loan, requestedBy string) (Loan, error) {
+	if loan.IsRecalled() {
+		return Loan{}, invalidTransition(b.state.Name, OpRecall,
+			fmt.Sprintf("book was already recalled for %s", loan.RecalledBy))
+	}
+	if requestedBy == loan.BorrowerID {
+		return Loan{}, invalidTransition(b.state.Name, OpRecall, "borrowers cannot recall their own loan")
+	}
+	now := b.clock.Now()
+	loan.RecalledAt = now
+	loan.RecalledBy = requestedBy
+	loan.DueAt = b.dueDate(b.recallPolicy.RecalledDueDate(loan, now))
+	return loan, nil
+}
+
+// finePolicyFor returns the fine policy of a loan, recalled loans are fined at the recall rate
+func (b *Book) finePolicyFor(loan Loan) FinePolicy {
+	policy := b.finePolicy
+	if loan.IsRecalled() && b.recallPolicy.DailyRate > 0 {
+		policy.DailyRate = b.recallPolicy.DailyRate
+		policy.MaterialRates = nil
+	}
+	return policy
+}
+
+// Notice is a message to a patron about one of their loans
+type Notice struct {
+	PatronID string
+	ISBN     string
+	Barcode  string
+	Subject  string
+	Message  string
+	SentAt   time.Time
+}
+
+// Notifier delivers notices to patrons
+type Notifier interface {
+	Notify(notice Notice) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface
+type NotifierFunc func(notice Notice) error
+
+// Notify calls the function
+func (nf NotifierFunc) Notify(notice Notice) error {
+	return nf(notice)
+}
+
+// RecallNotices returns an event handler that tells borrowers their loan was recalled
+// Subscribe it to an event bus; delivery failures are recorded as subscriber failures
+func RecallNotices(notifier Notifier) Handler {
+	return func(event Event) error {
+		if event.Operation != OpRecall || event.Loan == nil {
+			return nil
+		}
+		return notifier.Notify(Notice{
+			PatronID: event.PatronID,
+			ISBN:     event.ISBN,
+			Barcode:  event.Barcode,
+			Subject:  "Recall notice",
+			Message: fmt.Sprintf("Your loan %s has been recalled, please return it by %s",
+				event.Loan.ID, event.Loan.DueAt.Format(time.DateTime)),
+			SentAt: event.At,
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLateReturnOfRecalledLoanIsFinedAtRecallRate(t *testing.T) {
+	clock := NewManualClock(time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC))
+	fines := NewFineLedger()
+	book := newTestBook("Thinking, Fast and Slow", "9780374533557")
+	book.SetClock(clock)
+	book.SetFineLedger(fines)
+
+	if err := book.Borrow("student-123"); err != nil {
+		t.Fatal(err)
+	}
+	if err := book.Recall("faculty-42"); err != nil {
+		t.Fatal(err)
+	}
+	loan, _ := book.GetLoan()
+	clock.Advance(20 * 24 * time.Hour)
+	// returned straight from Borrowed, without MarkOverdue or a sweep first
+	if err := book.Return(); err != nil {
+		t.Fatal(err)
+	}
+
+	recorded := fines.ForPatron("student-123")
+	if len(recorded) != 1 {
+		t.Fatalf("%d fines recorded, want 1", len(recorded))
+	}
+	daysLate := DaysLate(loan.DueAt, clock.Now())
+	want := book.finePolicyFor(loan).Calculate(book.materialType, daysLate)
+	if recorded[0].Amount != want || recorded[0].DaysLate != daysLate {
+		t.Errorf("fine = %d for %d day(s), want %d for %d day(s)", recorded[0].Amount, recorded[0].DaysLate, want, daysLate)
+	}
+	if normal := book.finePolicy.Calculate(book.materialType, daysLate); want <= normal {
+		t.Errorf("recall fine %d is not above the normal fine %d", want, normal)
+	}
+}
+
+func TestOnTimeReturnIsNotFined(t *testing.T) {
+	fines := NewFineLedger()
+	book := newTestBook("Dune", "9780441172719")
+	book.SetFineLedger(fines)
+	if err := book.Borrow("student-123"); err != nil {
+		t.Fatal(err)
+	}
+	if err := book.Return(); err != nil {
+		t.Fatal(err)
+	}
+	if outstanding := fines.Outstanding("student-123"); outstanding != 0 {
+		t.Errorf("outstanding = %d, want 0", outstanding)
+	}
+}
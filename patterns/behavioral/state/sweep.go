@@ -0,0 +1,70 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// SweepResult describes a book the overdue sweep moved to OverdueState
+type SweepResult struct {
+	Title      string
+	ISBN       string
+	BorrowerID string
+	DueAt      time.Time
+}
+
+// SweepOverdue marks every borrowed book past its due date as overdue
+// and reports the books it changed; books changed by another operation during the sweep are skipped
+func SweepOverdue(clock Clock, books []*Book) []SweepResult {
+	now := clock.Now()
+	results := make([]SweepResult, 0)
+	for _, book := range books {
+		version := book.Version()
+		if name := book.GetStateName(); name != StateBorrowed && name != StateReserved {
+			continue
+		}
+		loan, _ := book.GetLoan()
+		if !loan.IsOverdue(now) {
+			continue
+		}
+		if err := book.AtVersion(version).MarkOverdue(); err != nil {
+			continue
+		}
+		results = append(results, SweepResult{
+			Title:      book.GetTitle(),
+			ISBN:       book.GetISBN(),
+			BorrowerID: loan.BorrowerID,
+			DueAt:      loan.DueAt,
+		})
+	}
+	return results
+}
+
+// SweepLost declares lost every overdue book past its lost item policy and bills the borrower
+// Books changed by another operation during the sweep are skipped
+func SweepLost(clock Clock, books []*Book) []SweepResult {
+	now := clock.Now()
+	results := make([]SweepResult, 0)
+	for _, book := range books {
+		loan, version, due := book.lostDue(now)
+		if !due {
+			continue
+		}
+		if err := book.AtVersion(version).DeclareLost(); err != nil {
+			continue
+		}
+		results = append(results, SweepResult{
+			Title:      book.GetTitle(),
+			ISBN:       book.GetISBN(),
+			BorrowerID: loan.BorrowerID,
+			DueAt:      loan.DueAt,
+		})
+	}
+	return results
+}
+
+// String returns a human readable description of the sweep result
+func (sr SweepResult) String() string {
+	return fmt.Sprintf("%s (ISBN: %s) borrowed by %s was due %s",
+		sr.Title, sr.ISBN, sr.BorrowerID, sr.DueAt.Format(time.DateTime))
+}
@@ -0,0 +1,89 @@
+package state
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSweepLostSkipsClosedDays(t *testing.T) {
+	checkout := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		calendar bool
+		at       time.Time
+		wantLost bool
+	}{
+		{name: "without calendar", at: time.Date(2025, time.January, 20, 12, 0, 0, 0, time.UTC), wantLost: true},
+		{name: "closure does not count", calendar: true, at: time.Date(2025, time.January, 20, 12, 0, 0, 0, time.UTC)},
+		{name: "enough open days after closure", calendar: true, at: time.Date(2025, time.January, 27, 12, 0, 0, 0, time.UTC), wantLost: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clock := NewManualClock(checkout)
+			book := newTestBook("The Alchemist", "9780062315007")
+			book.SetClock(clock)
+			book.SetLoanPeriod(7 * 24 * time.Hour)
+			book.SetLostItemPolicy(LostItemPolicy{DaysOverdue: 5, ReplacementCost: 2000})
+			if test.calendar {
+				calendar := NewCalendar("Main", nil)
+				if err := calendar.AddClosure(time.Date(2025, time.January, 14, 0, 0, 0, 0, time.UTC),
+					time.Date(2025, time.January, 21, 0, 0, 0, 0, time.UTC), "renovation"); err != nil {
+					t.Fatal(err)
+				}
+				book.SetCalendar(calendar)
+			}
+			if err := book.Borrow("student-123"); err != nil {
+				t.Fatal(err)
+			}
+			clock.Set(test.at)
+			if overdue := SweepOverdue(clock, []*Book{book}); len(overdue) != 1 {
+				t.Fatalf("%d book(s) marked overdue, want 1", len(overdue))
+			}
+
+			lost := SweepLost(clock, []*Book{book})
+			if got := len(lost) == 1; got != test.wantLost {
+				t.Errorf("declared lost = %v, want %v", got, test.wantLost)
+			}
+		})
+	}
+}
+
+func TestSweepLostWhilePolicyChanges(t *testing.T) {
+	clock := NewManualClock(time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC))
+	book := newTestBook("The Alchemist", "9780062315007")
+	book.SetClock(clock)
+	book.SetLoanPeriod(7 * 24 * time.Hour)
+	book.SetLostItemPolicy(LostItemPolicy{DaysOverdue: 1000})
+	if err := book.Borrow("student-123"); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(10 * 24 * time.Hour)
+	if overdue := SweepOverdue(clock, []*Book{book}); len(overdue) != 1 {
+		t.Fatalf("%d book(s) marked overdue, want 1", len(overdue))
+	}
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		<-start
+		for i := 0; i < 1000; i++ {
+			book.SetLostItemPolicy(LostItemPolicy{DaysOverdue: 1000 + i})
+			book.SetCalendar(NewCalendar("Main", nil))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		<-start
+		for i := 0; i < 1000; i++ {
+			SweepLost(clock, []*Book{book})
+		}
+	}()
+	close(start)
+	wg.Wait()
+	if name := book.GetStateName(); name != StateOverdue {
+		t.Errorf("state = %s, want %s", name, StateOverdue)
+	}
+}
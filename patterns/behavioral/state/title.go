@@ -0,0 +1,155 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoCopyAvailable is returned when every copy of a title is out or unavailable
+var ErrNoCopyAvailable = errors.New("no copy available")
+
+// Title groups the copies of one book, each copy tracking its own state
+type Title struct {
+	mu     sync.Mutex
+	name   string
+	isbn   string
+	copies []*Book
+}
+
+// NewTitle creates a title without copies
+func NewTitle(name, isbn string) *Title {
+	return &Title{name: name, isbn: isbn}
+}
+
+// GetName returns the title name
+func (t *Title) GetName() string {
+	return t.name
+}
+
+// GetISBN returns the title ISBN
+func (t *Title) GetISBN() string {
+	return t.isbn
+}
+
+// AddCopy creates a new available copy with the given barcode
+// The returned copy can be configured like any book (clock, loan period, event bus, ...)
+func (t *Title) AddCopy(barcode string) (*Book, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, item := range t.copies {
+		if item.barcode == barcode {
+			return nil, fmt.Errorf("copy with barcode '%s' already exists", barcode)
+		}
+	}
+	item := NewItem(t.name, t.isbn, barcode)
+	t.copies = append(t.copies, item)
+	return item, nil
+}
+
+// Copies returns all copies in the order they were added
+func (t *Title) Copies() []*Book {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*Book(nil), t.copies...)
+}
+
+// Copy returns the copy with the given barcode
+func (t *Title) Copy(barcode string) (*Book, bool) {
+	for _, item := range t.Copies() {
+		if item.barcode == barcode {
+			return item, true
+		}
+	}
+	return nil, false
+}
+
+// Borrow lends a copy to the borrower and returns it
+// A copy waiting on the hold shelf for the borrower is preferred, otherwise the first available copy is used
+func (t *Title) Borrow(borrowerID string) (*Book, error) {
+	candidates := make([]*Book, 0)
+	for _, item := range t.Copies() {
+		if item.GetStateName() == StateOnHoldShelf && item.GetHold() == borrowerID {
+			candidates = append([]*Book{item}, candidates...)
+		} else if item.GetStateName() == StateAvailable {
+			candidates = append(candidates, item)
+		}
+	}
+	for _, item := range candidates {
+		err := item.Borrow(borrowerID)
+		if err == nil {
+			return item, nil
+		}
+		if !errors.Is(err, ErrInvalidTransition) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("cannot borrow '%s': %w", t.name, ErrNoCopyAvailable)
+}
+
+// Return returns the copy with the given barcode
+func (t *Title) Return(barcode string) error {
+	item, exists := t.Copy(barcode)
+	if !exists {
+		return fmt.Errorf("no copy of '%s' with barcode '%s'", t.name, barcode)
+	}
+	return item.Return()
+}
+
+// TitleSummary aggregates the state of all copies of a title
+type TitleSummary struct {
+	Title       string
+	ISBN        string
+	Copies      int
+	ByState     map[string]int
+	NextDueAt   time.Time
+	NextDueCopy string
+	// States lists the states of the copies' machines in definition order
+	States []string
+}
+
+// Summary counts the copies per state and finds the loan due first
+func (t *Title) Summary() TitleSummary {
+	summary := TitleSummary{Title: t.name, ISBN: t.isbn, ByState: make(map[string]int)}
+	known := make(map[string]bool)
+	for _, item := range t.Copies() {
+		for _, name := range item.Machine().Definition().StateNames() {
+			if !known[name] {
+				known[name] = true
+				summary.States = append(summary.States, name)
+			}
+		}
+		summary.Copies++
+		summary.ByState[item.GetStateName()]++
+		if loan, ok := item.GetLoan(); ok && (summary.NextDueAt.IsZero() || loan.DueAt.Before(summary.NextDueAt)) {
+			summary.NextDueAt = loan.DueAt
+			summary.NextDueCopy = item.barcode
+		}
+	}
+	return summary
+}
+
+// Available returns the number of copies that can be borrowed right away
+func (ts TitleSummary) Available() int {
+	return ts.ByState[StateAvailable]
+}
+
+// String returns the counts per state in state machine order and the next due date
+func (ts TitleSummary) String() string {
+	counts := make([]string, 0)
+	for _, name := range ts.States {
+		if count := ts.ByState[name]; count > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", count, name))
+		}
+	}
+	summary := fmt.Sprintf("%s (ISBN: %s): %d copies", ts.Title, ts.ISBN, ts.Copies)
+	if len(counts) > 0 {
+		summary += ", " + strings.Join(counts, ", ")
+	}
+	if !ts.NextDueAt.IsZero() {
+		summary += fmt.Sprintf("; next due %s (%s)", ts.NextDueAt.Format(time.DateTime), ts.NextDueCopy)
+	}
+	return summary
+}
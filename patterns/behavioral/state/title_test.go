@@ -0,0 +1,39 @@
+package state
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSummaryListsStatesOfCustomMachine(t *testing.T) {
+	definition := DefaultDefinition()
+	definition.States = append(definition.States, StateDef{Name: "Quarantined", Label: "in quarantine"})
+	definition.Operations = append(definition.Operations,
+		OperationDef{Name: "Quarantine", Verb: "quarantine"},
+		OperationDef{Name: "Release", Verb: "release"})
+	definition.Transitions = append(definition.Transitions,
+		TransitionDef{From: StateAvailable, Operation: "Quarantine", To: "Quarantined"},
+		TransitionDef{From: "Quarantined", Operation: "Release", To: StateAvailable})
+	machine, err := NewMachine(definition, DefaultBehaviors())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	title := NewTitle("Pulang", "9786029144772")
+	for _, barcode := range []string{"PL-001", "PL-002"} {
+		item, err := title.AddCopy(barcode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		item.SetLogger(slog.New(slog.DiscardHandler))
+		item.SetMachine(machine)
+	}
+	item, _ := title.Copy("PL-002")
+	item.SetState(NewState("Quarantined"))
+
+	summary := title.Summary().String()
+	if !strings.Contains(summary, "1 Available, 1 Quarantined") {
+		t.Errorf("summary %q does not count the quarantined copy", summary)
+	}
+}
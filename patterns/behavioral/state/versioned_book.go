@@ -0,0 +1,75 @@
+package state
+
+import "time"
+
+// VersionedBook runs operations on a book only if it is still at the version the caller last saw
+// Operations on a book that has changed since fail with a ConflictError and change nothing
+type VersionedBook struct {
+	book    *Book
+	version uint64
+}
+
+// Version returns the version the operations expect
+func (vb *VersionedBook) Version() uint64 {
+	return vb.version
+}
+
+// Borrow attempts to borrow the book for the borrower
+func (vb *VersionedBook) Borrow(borrowerID string) error {
+	return vb.book.fire(&vb.version, Transition{Operation: OpBorrow, PatronID: borrowerID})
+}
+
+// Return attempts to return the book
+func (vb *VersionedBook) Return() error {
+	return vb.book.fire(&vb.version, Transition{Operation: OpReturn})
+}
+
+// MarkOverdue attempts to mark the book as overdue
+func (vb *VersionedBook) MarkOverdue() error {
+	return vb.book.fire(&vb.version, Transition{Operation: OpMarkOverdue})
+}
+
+// Renew attempts to extend the loan and returns the new due date
+func (vb *VersionedBook) Renew() (time.Time, error) {
+	return vb.book.renew(&vb.version)
+}
+
+// Recall asks the borrower to bring the book back early for another patron
+func (vb *VersionedBook) Recall(requestedBy string) error {
+	return vb.book.fire(&vb.version, Transition{Operation: OpRecall, PatronID: requestedBy})
+}
+
+// PlaceHold attempts to place a hold on the book for the patron
+func (vb *VersionedBook) PlaceHold(patronID string) error {
+	return vb.book.fire(&vb.version, Transition{Operation: OpPlaceHold, PatronID: patronID})
+}
+
+// CancelHold attempts to cancel the hold on the book
+func (vb *VersionedBook) CancelHold() error {
+	return vb.book.fire(&vb.version, Transition{Operation: OpCancelHold})
+}
+
+// Ship attempts to send the book to another branch
+func (vb *VersionedBook) Ship(destination string) error {
+	return vb.book.fire(&vb.version, Transition{Operation: OpShip, Destination: destination})
+}
+
+// Receive attempts to check the book in after transit or after it was found
+func (vb *VersionedBook) Receive() error {
+	return vb.book.fire(&vb.version, Transition{Operation: OpReceive})
+}
+
+// DeclareLost attempts to declare the book lost
+func (vb *VersionedBook) DeclareLost() error {
+	return vb.book.fire(&vb.version, Transition{Operation: OpDeclareLost})
+}
+
+// Withdraw attempts to remove the book from the collection
+func (vb *VersionedBook) Withdraw() error {
+	return vb.book.fire(&vb.version, Transition{Operation: OpWithdraw})
+}
+
+// ClaimReturned records the borrower's claim that the book was already returned
+func (vb *VersionedBook) ClaimReturned() error {
+	return vb.book.fire(&vb.version, Transition{Operation: OpClaimReturned})
+}
@@ -1,11 +1,14 @@
 package strategy
 
 // Book represents a book for searching
+// Suppressed books are hidden from public searches and flagged in staff searches
 type Book struct {
-	Title    string
-	Author   string
-	ISBN     string
-	Category string
+	Title             string
+	Author            string
+	ISBN              string
+	Category          string
+	Suppressed        bool
+	SuppressionReason string
 }
 
 // GetTitle returns the book title
@@ -27,3 +30,8 @@ func (b *Book) GetISBN() string {
 func (b *Book) GetCategory() string {
 	return b.Category
 }
+
+// IsSuppressed checks if the book is hidden from the public catalog
+func (b *Book) IsSuppressed() bool {
+	return b.Suppressed
+}
@@ -1,18 +1,25 @@
 package strategy
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+
+	"library-management-system/internal/render"
+)
 
 // Catalog uses search strategy to find books
 type Catalog struct {
 	books    []Book
 	strategy SearchStrategy
+	mode     SearchMode
 }
 
-// NewCatalog creates a new catalog with default TitleSearchStrategy
+// NewCatalog creates a new public catalog with default TitleSearchStrategy
 func NewCatalog(books []Book) *Catalog {
 	return &Catalog{
 		books:    books,
 		strategy: NewTitleSearchStrategy(),
+		mode:     PublicSearch,
 	}
 }
 
@@ -21,9 +28,34 @@ func (c *Catalog) SetStrategy(strategy SearchStrategy) {
 	c.strategy = strategy
 }
 
+// SetMode changes who the catalog searches for
+func (c *Catalog) SetMode(mode SearchMode) {
+	c.mode = mode
+}
+
+// GetMode returns the current search mode
+func (c *Catalog) GetMode() SearchMode {
+	return c.mode
+}
+
 // Find searches for books using the current strategy
+// Public searches skip suppressed books
 func (c *Catalog) Find(query string) []Book {
-	return c.strategy.Search(query, c.books)
+	return c.strategy.Search(query, c.visibleBooks())
+}
+
+// visibleBooks returns the books the current search mode may see
+func (c *Catalog) visibleBooks() []Book {
+	if c.mode == StaffSearch {
+		return c.books
+	}
+	visible := make([]Book, 0, len(c.books))
+	for _, book := range c.books {
+		if !book.IsSuppressed() {
+			visible = append(visible, book)
+		}
+	}
+	return visible
 }
 
 // AddBook adds a book to the catalog
@@ -41,29 +73,54 @@ func (c *Catalog) GetBookCount() int {
 	return len(c.books)
 }
 
-// DisplayCatalog displays all books in the catalog
-func (c *Catalog) DisplayCatalog() {
-	fmt.Printf("Catalog contains %d books:\n", len(c.books))
-	for i, book := range c.books {
-		fmt.Printf("  %d. %s by %s (ISBN: %s)\n", i+1, book.Title, book.Author, book.ISBN)
-	}
+// DisplayCatalog writes all books visible in the current search mode with the given renderer
+func (c *Catalog) DisplayCatalog(w io.Writer, renderer render.Renderer) error {
+	books := c.visibleBooks()
+	report := booksReport(books)
+	report.Title = fmt.Sprintf("Catalog contains %d books:", len(books))
+	return renderer.Render(w, report)
 }
 
-// DisplayResults displays search results
-func (c *Catalog) DisplayResults(results []Book, query string) {
+// DisplayResults writes search results with the given renderer
+func (c *Catalog) DisplayResults(w io.Writer, renderer render.Renderer, results []Book, query string) error {
+	report := booksReport(results)
 	if c.strategy != nil {
-		fmt.Printf("\nSearch Results (%s) for '%s':\n", c.strategy.GetStrategyName(), query)
+		report.Title = fmt.Sprintf("Search Results (%s) for '%s':", c.strategy.GetStrategyName(), query)
 	} else {
-		fmt.Printf("\nSearch Results for '%s':\n", query)
+		report.Title = fmt.Sprintf("Search Results for '%s':", query)
 	}
+	report.Empty = "No results found"
+	if len(results) > 0 {
+		report.Footer = fmt.Sprintf("Found %d result(s)", len(results))
+	}
+	return renderer.Render(w, report)
+}
 
-	if len(results) == 0 {
-		fmt.Println("  No results found")
-		return
+// booksReport lists books with their suppression reason, shown as a staff flag in plain text
+func booksReport(books []Book) render.Report {
+	report := render.Report{
+		Columns: []string{"title", "author", "isbn", "category", "suppressed"},
+		Line: func(i int, row []string) string {
+			flag := ""
+			if row[4] != "" {
+				flag = fmt.Sprintf(" [Suppressed: %s]", row[4])
+			}
+			return fmt.Sprintf("%d. %s by %s (ISBN: %s)%s", i+1, row[0], row[1], row[2], flag)
+		},
 	}
+	for _, book := range books {
+		report.Rows = append(report.Rows, []string{book.Title, book.Author, book.ISBN, book.Category, suppressionReason(book)})
+	}
+	return report
+}
 
-	for i, book := range results {
-		fmt.Printf("  %d. %s by %s (ISBN: %s)\n", i+1, book.Title, book.Author, book.ISBN)
+// suppressionReason returns why a book is suppressed, empty for visible books
+func suppressionReason(book Book) string {
+	if !book.IsSuppressed() {
+		return ""
+	}
+	if book.SuppressionReason == "" {
+		return "suppressed"
 	}
-	fmt.Printf("  Found %d result(s)\n", len(results))
+	return book.SuppressionReason
 }
@@ -0,0 +1,19 @@
+package strategy
+
+// SearchMode defines who is searching the catalog
+type SearchMode int
+
+const (
+	// PublicSearch skips suppressed books (OPAC)
+	PublicSearch SearchMode = iota
+	// StaffSearch includes suppressed books and flags them
+	StaffSearch
+)
+
+// String returns the search mode name
+func (sm SearchMode) String() string {
+	if sm == StaffSearch {
+		return "Staff"
+	}
+	return "Public"
+}
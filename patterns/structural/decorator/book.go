@@ -1,14 +1,28 @@
 package decorator
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
 
 // Book represents a base book
+// Copies is the number of copies the library owns; loaned, held and repaired copies are tracked
+// separately so the owned total never changes through circulation
 type Book struct {
-	Title     string
-	Author    string
-	ISBN      string
-	Publisher string
-	Copies    int
+	Title      string
+	Author     string
+	ISBN       string
+	Publisher  string
+	Category   string
+	Collection string
+	Tags       []string
+	Copies     int
+
+	mu        sync.Mutex
+	loaned    int
+	held      int
+	repairing int
 }
 
 // GetTitle returns the book title
@@ -26,15 +40,31 @@ func (b *Book) GetISBN() string {
 	return b.ISBN
 }
 
+// HasTag checks if the book carries the given tag (case-insensitive)
+func (b *Book) HasTag(tag string) bool {
+	for _, t := range b.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetDetails returns book details
 func (b *Book) GetDetails() string {
-	return fmt.Sprintf("Book{Title: %s, Author: %s, ISBN: %s, Copies: %d}",
-		b.Title, b.Author, b.ISBN, b.Copies)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	repair := ""
+	if b.repairing > 0 {
+		repair = fmt.Sprintf(", Under Repair: %d", b.repairing)
+	}
+	return fmt.Sprintf("Book{Title: %s, Author: %s, ISBN: %s, Copies: %d, Available: %d%s}",
+		b.Title, b.Author, b.ISBN, b.Copies, b.availableLocked(), repair)
 }
 
-// CanBorrow checks if the book can be borrowed
+// CanBorrow checks if the book has an available copy
 func (b *Book) CanBorrow() bool {
-	return b.Copies > 0
+	return b.AvailableCopies() > 0
 }
 
 // CanReadInLibrary checks if the book can be read in the library
@@ -42,14 +72,111 @@ func (b *Book) CanReadInLibrary() bool {
 	return true
 }
 
-// Borrow reduces the number of available copies
-func (b *Book) Borrow() {
-	if b.Copies > 0 {
-		b.Copies--
+// GetLoanRules returns the default loan rules for an undecorated book
+func (b *Book) GetLoanRules() LoanRules {
+	return DefaultLoanRules()
+}
+
+// GetFees returns no fees for an undecorated book
+func (b *Book) GetFees() []Fee {
+	return nil
+}
+
+// Borrow lends one available copy
+func (b *Book) Borrow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.availableLocked() <= 0 {
+		return fmt.Errorf("no copies of '%s' are available", b.Title)
+	}
+	b.loaned++
+	return nil
+}
+
+// Return takes back one loaned copy
+func (b *Book) Return() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.loaned <= 0 {
+		return fmt.Errorf("no copies of '%s' are on loan", b.Title)
+	}
+	b.loaned--
+	return nil
+}
+
+// Hold sets one available copy aside on the hold shelf
+func (b *Book) Hold() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.availableLocked() <= 0 {
+		return fmt.Errorf("no copies of '%s' are available to hold", b.Title)
+	}
+	b.held++
+	return nil
+}
+
+// ReleaseHold puts one held copy back on the shelf
+func (b *Book) ReleaseHold() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.held <= 0 {
+		return fmt.Errorf("no copies of '%s' are on hold", b.Title)
+	}
+	b.held--
+	return nil
+}
+
+// SendForRepair takes one available copy out of service while it is repaired
+func (b *Book) SendForRepair() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.availableLocked() <= 0 {
+		return fmt.Errorf("no copies of '%s' are available to send for repair", b.Title)
+	}
+	b.repairing++
+	return nil
+}
+
+// ReceiveFromRepair puts one repaired copy back on the shelf
+func (b *Book) ReceiveFromRepair() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.repairing <= 0 {
+		return fmt.Errorf("no copies of '%s' are out for repair", b.Title)
 	}
+	b.repairing--
+	return nil
+}
+
+// AvailableCopies returns the number of copies on the shelf
+func (b *Book) AvailableCopies() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.availableLocked()
+}
+
+// LoanedCopies returns the number of copies currently on loan
+func (b *Book) LoanedCopies() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.loaned
+}
+
+// HeldCopies returns the number of copies on the hold shelf
+func (b *Book) HeldCopies() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.held
+}
+
+// RepairCopies returns the number of copies out for repair
+func (b *Book) RepairCopies() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.repairing
 }
 
-// Return increases the number of available copies
-func (b *Book) Return() {
-	b.Copies++
+// availableLocked computes the available copies, the caller must hold the lock
+func (b *Book) availableLocked() int {
+	return b.Copies - b.loaned - b.held - b.repairing
 }
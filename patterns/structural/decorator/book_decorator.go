@@ -9,8 +9,10 @@ type BookComponent interface {
 	GetDetails() string
 	CanBorrow() bool
 	CanReadInLibrary() bool
-	Borrow()
-	Return()
+	GetLoanRules() LoanRules
+	GetFees() []Fee
+	Borrow() error
+	Return() error
 }
 
 // BaseBookDecorator provides default delegation to the wrapped component
@@ -39,6 +41,26 @@ func (d *BaseBookDecorator) GetDetails() string {
 	return d.Component.GetDetails()
 }
 
+// loanlessDescriber is implemented by decorators that can describe the book without loan period tags
+// An outer loan period decorator describes its wrapped stack this way and prints the composed rules once;
+// decorators overriding GetDetails must override detailsWithoutLoan with the same info
+type loanlessDescriber interface {
+	detailsWithoutLoan() string
+}
+
+// detailsWithoutLoan describes the book without the tags of loan period decorators in it
+func detailsWithoutLoan(book BookComponent) string {
+	if describer, ok := book.(loanlessDescriber); ok {
+		return describer.detailsWithoutLoan()
+	}
+	return book.GetDetails()
+}
+
+// detailsWithoutLoan delegates to the wrapped component without loan period tags
+func (d *BaseBookDecorator) detailsWithoutLoan() string {
+	return detailsWithoutLoan(d.Component)
+}
+
 // CanBorrow delegates to the wrapped component
 func (d *BaseBookDecorator) CanBorrow() bool {
 	return d.Component.CanBorrow()
@@ -49,12 +71,66 @@ func (d *BaseBookDecorator) CanReadInLibrary() bool {
 	return d.Component.CanReadInLibrary()
 }
 
+// GetLoanRules delegates to the wrapped component
+func (d *BaseBookDecorator) GetLoanRules() LoanRules {
+	return d.Component.GetLoanRules()
+}
+
+// GetFees delegates to the wrapped component
+func (d *BaseBookDecorator) GetFees() []Fee {
+	return d.Component.GetFees()
+}
+
+// Unwrap returns the wrapped component so the decorator stack can be inspected
+func (d *BaseBookDecorator) Unwrap() BookComponent {
+	return d.Component
+}
+
+// unwrapComponent returns the component wrapped by book, or nil for an undecorated book
+func unwrapComponent(book BookComponent) BookComponent {
+	if wrapper, ok := book.(interface{ Unwrap() BookComponent }); ok {
+		return wrapper.Unwrap()
+	}
+	return nil
+}
+
+// baseBook returns the undecorated book at the bottom of the decorator stack
+func baseBook(book BookComponent) (*Book, bool) {
+	for book != nil {
+		if base, ok := book.(*Book); ok {
+			return base, true
+		}
+		book = unwrapComponent(book)
+	}
+	return nil, false
+}
+
 // Borrow delegates to the wrapped component
-func (d *BaseBookDecorator) Borrow() {
-	d.Component.Borrow()
+func (d *BaseBookDecorator) Borrow() error {
+	return d.Component.Borrow()
+}
+
+// unchargedBorrower is implemented by decorators that can borrow without posting fees
+// A fee decorator borrows its wrapped stack this way, so only the outermost fee decorator charges;
+// decorators overriding Borrow must override borrowUncharged with the same checks
+type unchargedBorrower interface {
+	borrowUncharged() error
+}
+
+// borrowUncharged borrows the book without letting fee decorators in it post charges
+func borrowUncharged(book BookComponent) error {
+	if borrower, ok := book.(unchargedBorrower); ok {
+		return borrower.borrowUncharged()
+	}
+	return book.Borrow()
+}
+
+// borrowUncharged delegates to the wrapped component without charging fees
+func (d *BaseBookDecorator) borrowUncharged() error {
+	return borrowUncharged(d.Component)
 }
 
 // Return delegates to the wrapped component
-func (d *BaseBookDecorator) Return() {
-	d.Component.Return()
+func (d *BaseBookDecorator) Return() error {
+	return d.Component.Return()
 }
@@ -0,0 +1,84 @@
+package decorator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// BookRepository stores base books and decorates them with the circulation policy on fetch
+type BookRepository struct {
+	books  map[string]*Book
+	policy *CirculationPolicy
+}
+
+// NewBookRepository creates a new repository using the given policy
+func NewBookRepository(policy *CirculationPolicy) *BookRepository {
+	if policy == nil {
+		policy = &CirculationPolicy{}
+	}
+	return &BookRepository{
+		books:  make(map[string]*Book),
+		policy: policy,
+	}
+}
+
+// AddBook adds a base book to the repository
+func (br *BookRepository) AddBook(book *Book) {
+	br.books[book.ISBN] = book
+}
+
+// Book returns the undecorated book with the given ISBN
+func (br *BookRepository) Book(isbn string) (*Book, bool) {
+	book, exists := br.books[isbn]
+	return book, exists
+}
+
+// Fetch returns the book with the given ISBN wrapped by every matching policy
+func (br *BookRepository) Fetch(isbn string) (BookComponent, error) {
+	return br.FetchFor(isbn, nil)
+}
+
+// FetchFor fetches the book for a patron, fee policies post their charges to the account
+func (br *BookRepository) FetchFor(isbn string, account *PatronAccount) (BookComponent, error) {
+	book, exists := br.books[isbn]
+	if !exists {
+		return nil, fmt.Errorf("book with ISBN '%s' not found", isbn)
+	}
+	return br.policy.Apply(book, account), nil
+}
+
+// DryRun returns the policy rules that would be applied to the book without decorating it
+func (br *BookRepository) DryRun(isbn string) (*Book, []PolicyRule, error) {
+	book, exists := br.books[isbn]
+	if !exists {
+		return nil, nil, fmt.Errorf("book with ISBN '%s' not found", isbn)
+	}
+	return book, br.policy.MatchingRules(book), nil
+}
+
+// LoadBooksFile reads the library's base books from a JSON file into a repository using the given policy
+func LoadBooksFile(path string, policy *CirculationPolicy) (*BookRepository, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read books file: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var books []*Book
+	if err := decoder.Decode(&books); err != nil {
+		return nil, fmt.Errorf("invalid books file: %w", err)
+	}
+	repository := NewBookRepository(policy)
+	for i, book := range books {
+		if book.ISBN == "" || book.Copies < 1 {
+			return nil, fmt.Errorf("book %d requires an isbn and at least one copy", i+1)
+		}
+		if _, exists := repository.Book(book.ISBN); exists {
+			return nil, fmt.Errorf("book %d: ISBN '%s' is listed twice", i+1, book.ISBN)
+		}
+		repository.AddBook(book)
+	}
+	return repository, nil
+}
@@ -0,0 +1,145 @@
+package decorator
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestBookCirculation(t *testing.T) {
+	tests := []struct {
+		name      string
+		copies    int
+		steps     []func(*Book) error
+		wantErr   []bool
+		available int
+	}{
+		{
+			name:      "borrow and return",
+			copies:    1,
+			steps:     []func(*Book) error{(*Book).Borrow, (*Book).Return},
+			wantErr:   []bool{false, false},
+			available: 1,
+		},
+		{
+			name:      "borrow beyond the owned copies",
+			copies:    1,
+			steps:     []func(*Book) error{(*Book).Borrow, (*Book).Borrow},
+			wantErr:   []bool{false, true},
+			available: 0,
+		},
+		{
+			name:      "return without a loan",
+			copies:    2,
+			steps:     []func(*Book) error{(*Book).Borrow, (*Book).Return, (*Book).Return},
+			wantErr:   []bool{false, false, true},
+			available: 2,
+		},
+		{
+			name:      "held copy cannot be borrowed",
+			copies:    1,
+			steps:     []func(*Book) error{(*Book).Hold, (*Book).Borrow, (*Book).ReleaseHold, (*Book).Borrow},
+			wantErr:   []bool{false, true, false, false},
+			available: 0,
+		},
+		{
+			name:      "release without a hold",
+			copies:    1,
+			steps:     []func(*Book) error{(*Book).ReleaseHold},
+			wantErr:   []bool{true},
+			available: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			book := &Book{Title: "Clean Code", ISBN: "9780132350884", Copies: test.copies}
+			for i, step := range test.steps {
+				if err := step(book); (err != nil) != test.wantErr[i] {
+					t.Errorf("step %d: got %v, want error %t", i+1, err, test.wantErr[i])
+				}
+			}
+			if available := book.AvailableCopies(); available != test.available {
+				t.Errorf("available = %d, want %d", available, test.available)
+			}
+			if owned := book.Copies; owned != test.copies {
+				t.Errorf("owned copies changed to %d", owned)
+			}
+		})
+	}
+}
+
+func TestConcurrentDesksNeverOverbook(t *testing.T) {
+	const (
+		copies = 5
+		desks  = 32
+		rounds = 200
+	)
+	book := &Book{Title: "Clean Code", ISBN: "9780132350884", Copies: copies}
+
+	var wg sync.WaitGroup
+	for desk := range desks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for round := range rounds {
+				if (desk+round)%2 == 0 {
+					if book.Borrow() == nil {
+						if err := book.Return(); err != nil {
+							t.Errorf("desk %d could not return its loan: %v", desk, err)
+						}
+					}
+				} else if book.Hold() == nil {
+					if err := book.ReleaseHold(); err != nil {
+						t.Errorf("desk %d could not release its hold: %v", desk, err)
+					}
+				}
+				if available := book.AvailableCopies(); available < 0 || available > copies {
+					t.Errorf("available = %d, want between 0 and %d", available, copies)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if loaned, held := book.LoanedCopies(), book.HeldCopies(); loaned != 0 || held != 0 {
+		t.Errorf("loaned %d, held %d after all desks finished, want 0 and 0", loaned, held)
+	}
+	if err := book.Return(); err == nil {
+		t.Error("return accepted with no copies on loan")
+	}
+	if err := book.ReleaseHold(); err == nil {
+		t.Error("hold release accepted with no copies on hold")
+	}
+}
+
+func TestConcurrentBorrowsStopAtOwnedCopies(t *testing.T) {
+	const copies = 3
+	book := &Book{Title: "Clean Code", ISBN: "9780132350884", Copies: copies}
+
+	var wg sync.WaitGroup
+	results := make([]error, 20)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i%2 == 0 {
+				results[i] = book.Borrow()
+			} else {
+				results[i] = book.Hold()
+			}
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range results {
+		if err == nil {
+			succeeded++
+		}
+	}
+	if succeeded != copies {
+		t.Errorf("%d borrows and holds succeeded, want %d", succeeded, copies)
+	}
+	if loaned, held := book.LoanedCopies(), book.HeldCopies(); loaned+held != copies {
+		t.Errorf("loaned %d + held %d, want %d", loaned, held, copies)
+	}
+}
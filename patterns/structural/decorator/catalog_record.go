@@ -0,0 +1,17 @@
+package decorator
+
+import "library-management-system/patterns/behavioral/strategy"
+
+// CatalogRecord converts a decorated book into a catalog record
+// A suppressed decorator anywhere in the stack hides the record from public searches
+func CatalogRecord(book BookComponent, category string) strategy.Book {
+	reason, suppressed := Suppression(book)
+	return strategy.Book{
+		Title:             book.GetTitle(),
+		Author:            book.GetAuthor(),
+		ISBN:              book.GetISBN(),
+		Category:          category,
+		Suppressed:        suppressed,
+		SuppressionReason: reason,
+	}
+}
@@ -0,0 +1,214 @@
+package decorator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"library-management-system/internal/render"
+)
+
+// DigitalLicenseBookDecorator wraps an e-book whose license limits simultaneous loans,
+// total checkouts and the license lifetime
+// Embeds BaseBookDecorator for default delegation, overrides only changed behavior;
+// the license counters are safe for concurrent use
+type DigitalLicenseBookDecorator struct {
+	BaseBookDecorator
+	mu           sync.Mutex
+	seats        int
+	activeSeats  int
+	maxCheckouts int
+	checkouts    int
+	expiresAt    time.Time
+	clock        func() time.Time
+}
+
+// NewDigitalLicenseBookDecorator creates a new digital license decorator
+// maxCheckouts of 0 means unlimited checkouts, a zero expiresAt means the license never expires;
+// loans are also passed to the wrapped e-book, which should own a copy for every seat
+func NewDigitalLicenseBookDecorator(book BookComponent, seats, maxCheckouts int, expiresAt time.Time) *DigitalLicenseBookDecorator {
+	return &DigitalLicenseBookDecorator{
+		BaseBookDecorator: BaseBookDecorator{Component: book},
+		seats:             seats,
+		maxCheckouts:      maxCheckouts,
+		expiresAt:         expiresAt,
+		clock:             time.Now,
+	}
+}
+
+// SetClock replaces the clock used to check license expiry
+func (dld *DigitalLicenseBookDecorator) SetClock(clock func() time.Time) {
+	dld.mu.Lock()
+	defer dld.mu.Unlock()
+	dld.clock = clock
+}
+
+// GetDetails returns the book details with license usage info
+func (dld *DigitalLicenseBookDecorator) GetDetails() string {
+	return dld.details(dld.Component.GetDetails())
+}
+
+// detailsWithoutLoan describes the book like GetDetails for an outer loan period decorator
+func (dld *DigitalLicenseBookDecorator) detailsWithoutLoan() string {
+	return dld.details(detailsWithoutLoan(dld.Component))
+}
+
+// details appends the license usage info to the details of the wrapped book
+func (dld *DigitalLicenseBookDecorator) details(inner string) string {
+	dld.mu.Lock()
+	defer dld.mu.Unlock()
+	checkouts := fmt.Sprintf("%d checkouts", dld.checkouts)
+	if dld.maxCheckouts > 0 {
+		checkouts = fmt.Sprintf("%d/%d checkouts", dld.checkouts, dld.maxCheckouts)
+	}
+	expiry := "no expiry"
+	if !dld.expiresAt.IsZero() {
+		expiry = "expires " + dld.expiresAt.Format(time.DateOnly)
+	}
+	return fmt.Sprintf("%s [Digital License: %d/%d seats in use, %s, %s]",
+		inner, dld.activeSeats, dld.seats, checkouts, expiry)
+}
+
+// CanBorrow checks the license limits and the wrapped component
+func (dld *DigitalLicenseBookDecorator) CanBorrow() bool {
+	dld.mu.Lock()
+	reason := dld.denialReasonLocked()
+	dld.mu.Unlock()
+	return reason == "" && dld.Component.CanBorrow()
+}
+
+// Borrow takes a license seat and counts the checkout once the wrapped component lends the book
+func (dld *DigitalLicenseBookDecorator) Borrow() error {
+	return dld.borrow(dld.Component.Borrow)
+}
+
+// borrowUncharged takes a license seat like Borrow for an outer fee decorator
+func (dld *DigitalLicenseBookDecorator) borrowUncharged() error {
+	return dld.borrow(dld.BaseBookDecorator.borrowUncharged)
+}
+
+// borrow checks the license, runs the borrow of the wrapped component and takes the seat
+// The lock is held across the wrapped borrow so concurrent loans cannot overrun the seats
+func (dld *DigitalLicenseBookDecorator) borrow(next func() error) error {
+	dld.mu.Lock()
+	defer dld.mu.Unlock()
+	if reason := dld.denialReasonLocked(); reason != "" {
+		return fmt.Errorf("cannot borrow e-book: %s", reason)
+	}
+	if err := next(); err != nil {
+		return err
+	}
+	dld.activeSeats++
+	dld.checkouts++
+	return nil
+}
+
+// Return passes the return to the wrapped component and releases a license seat
+func (dld *DigitalLicenseBookDecorator) Return() error {
+	dld.mu.Lock()
+	defer dld.mu.Unlock()
+	if dld.activeSeats == 0 {
+		return fmt.Errorf("cannot return an e-book that has no active loans")
+	}
+	if err := dld.Component.Return(); err != nil {
+		return err
+	}
+	dld.activeSeats--
+	return nil
+}
+
+// GetActiveSeats returns the number of seats currently on loan
+func (dld *DigitalLicenseBookDecorator) GetActiveSeats() int {
+	dld.mu.Lock()
+	defer dld.mu.Unlock()
+	return dld.activeSeats
+}
+
+// GetCheckouts returns the number of checkouts made under the license
+func (dld *DigitalLicenseBookDecorator) GetCheckouts() int {
+	dld.mu.Lock()
+	defer dld.mu.Unlock()
+	return dld.checkouts
+}
+
+// IsExpired checks if the license has passed its expiry date
+func (dld *DigitalLicenseBookDecorator) IsExpired() bool {
+	dld.mu.Lock()
+	defer dld.mu.Unlock()
+	return dld.isExpiredLocked()
+}
+
+// isExpiredLocked checks the expiry date, the caller must hold dld.mu
+func (dld *DigitalLicenseBookDecorator) isExpiredLocked() bool {
+	return !dld.expiresAt.IsZero() && !dld.clock().Before(dld.expiresAt)
+}
+
+// RemainingCheckouts returns the checkouts left on the license, or -1 when unlimited
+func (dld *DigitalLicenseBookDecorator) RemainingCheckouts() int {
+	dld.mu.Lock()
+	defer dld.mu.Unlock()
+	return dld.remainingCheckoutsLocked()
+}
+
+// remainingCheckoutsLocked returns the checkouts left, the caller must hold dld.mu
+func (dld *DigitalLicenseBookDecorator) remainingCheckoutsLocked() int {
+	if dld.maxCheckouts == 0 {
+		return -1
+	}
+	return dld.maxCheckouts - dld.checkouts
+}
+
+// denialReasonLocked returns why the license cannot lend another copy, or an empty string
+// The caller must hold dld.mu
+func (dld *DigitalLicenseBookDecorator) denialReasonLocked() string {
+	if dld.isExpiredLocked() {
+		return "license expired"
+	}
+	if dld.maxCheckouts > 0 && dld.checkouts >= dld.maxCheckouts {
+		return "license checkouts exhausted"
+	}
+	if dld.activeSeats >= dld.seats {
+		return "all license seats are in use"
+	}
+	return ""
+}
+
+// LicenseThreshold defines when a license counts as approaching exhaustion
+type LicenseThreshold struct {
+	RemainingCheckouts int
+	ExpiresWithin      time.Duration
+}
+
+// NearingExhaustion returns the reasons the license is close to running out, if any
+func (dld *DigitalLicenseBookDecorator) NearingExhaustion(threshold LicenseThreshold) []string {
+	dld.mu.Lock()
+	defer dld.mu.Unlock()
+	reasons := make([]string, 0)
+	if remaining := dld.remainingCheckoutsLocked(); remaining >= 0 && remaining <= threshold.RemainingCheckouts {
+		reasons = append(reasons, fmt.Sprintf("%d checkout(s) left", remaining))
+	}
+	if !dld.expiresAt.IsZero() && dld.expiresAt.Sub(dld.clock()) <= threshold.ExpiresWithin {
+		reasons = append(reasons, "expires "+dld.expiresAt.Format(time.DateOnly))
+	}
+	return reasons
+}
+
+// DisplayLicenseReport writes the licenses approaching exhaustion with the given renderer
+func DisplayLicenseReport(w io.Writer, renderer render.Renderer, licenses []*DigitalLicenseBookDecorator, threshold LicenseThreshold) error {
+	report := render.Report{
+		Title:   "Licenses approaching exhaustion:",
+		Columns: []string{"title", "isbn", "reasons"},
+		Line: func(i int, row []string) string {
+			return fmt.Sprintf("%d. %s (ISBN: %s) - %s", i+1, row[0], row[1], row[2])
+		},
+		Empty: "None",
+	}
+	for _, license := range licenses {
+		if reasons := license.NearingExhaustion(threshold); len(reasons) > 0 {
+			report.Rows = append(report.Rows, []string{license.GetTitle(), license.GetISBN(), strings.Join(reasons, ", ")})
+		}
+	}
+	return renderer.Render(w, report)
+}
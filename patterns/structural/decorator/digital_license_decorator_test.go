@@ -0,0 +1,191 @@
+package decorator
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDigitalLicenseLimits(t *testing.T) {
+	now := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		seats        int
+		maxCheckouts int
+		expiresAt    time.Time
+		steps        []func(*DigitalLicenseBookDecorator) error
+		wantErr      string
+	}{
+		{
+			name:  "seats in use",
+			seats: 2,
+			steps: []func(*DigitalLicenseBookDecorator) error{
+				(*DigitalLicenseBookDecorator).Borrow, (*DigitalLicenseBookDecorator).Borrow, (*DigitalLicenseBookDecorator).Borrow,
+			},
+			wantErr: "all license seats are in use",
+		},
+		{
+			name:  "returned seat can be lent again",
+			seats: 1,
+			steps: []func(*DigitalLicenseBookDecorator) error{
+				(*DigitalLicenseBookDecorator).Borrow, (*DigitalLicenseBookDecorator).Return, (*DigitalLicenseBookDecorator).Borrow,
+			},
+		},
+		{
+			name:         "checkouts exhausted",
+			seats:        2,
+			maxCheckouts: 2,
+			steps: []func(*DigitalLicenseBookDecorator) error{
+				(*DigitalLicenseBookDecorator).Borrow, (*DigitalLicenseBookDecorator).Return,
+				(*DigitalLicenseBookDecorator).Borrow, (*DigitalLicenseBookDecorator).Return,
+				(*DigitalLicenseBookDecorator).Borrow,
+			},
+			wantErr: "license checkouts exhausted",
+		},
+		{
+			name:      "expired",
+			seats:     1,
+			expiresAt: now,
+			steps:     []func(*DigitalLicenseBookDecorator) error{(*DigitalLicenseBookDecorator).Borrow},
+			wantErr:   "license expired",
+		},
+		{
+			name:    "return without a loan",
+			seats:   1,
+			steps:   []func(*DigitalLicenseBookDecorator) error{(*DigitalLicenseBookDecorator).Return},
+			wantErr: "no active loans",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ebook := &Book{Title: "Go Programming Language", ISBN: "9780134190440", Copies: test.seats}
+			license := NewDigitalLicenseBookDecorator(ebook, test.seats, test.maxCheckouts, test.expiresAt)
+			license.SetClock(func() time.Time { return now })
+			var err error
+			for _, step := range test.steps {
+				if err = step(license); err != nil {
+					break
+				}
+			}
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("got %v, want %q", err, test.wantErr)
+			}
+			if license.CanBorrow() && !strings.Contains(test.wantErr, "no active loans") {
+				t.Error("CanBorrow is true after the limit was reached")
+			}
+		})
+	}
+}
+
+func TestDigitalLicenseNearingExhaustion(t *testing.T) {
+	now := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	threshold := LicenseThreshold{RemainingCheckouts: 3, ExpiresWithin: 7 * 24 * time.Hour}
+	tests := []struct {
+		name         string
+		maxCheckouts int
+		expiresAt    time.Time
+		want         []string
+	}{
+		{name: "plenty left", maxCheckouts: 20, expiresAt: now.AddDate(1, 0, 0)},
+		{name: "unlimited, no expiry"},
+		{name: "few checkouts", maxCheckouts: 3, want: []string{"3 checkout(s) left"}},
+		{name: "expiring soon", expiresAt: now.AddDate(0, 0, 5), want: []string{"expires 2025-01-11"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			license := NewDigitalLicenseBookDecorator(&Book{Copies: 1}, 1, test.maxCheckouts, test.expiresAt)
+			license.SetClock(func() time.Time { return now })
+			got := license.NearingExhaustion(threshold)
+			if strings.Join(got, "; ") != strings.Join(test.want, "; ") {
+				t.Errorf("reasons = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDigitalLicenseDelegatesToWrappedBook(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrap    func(*Book) BookComponent
+		wantErr string
+	}{
+		{
+			name: "wrapped book lends",
+			wrap: func(book *Book) BookComponent { return book },
+		},
+		{
+			name:    "wrapped decorator refuses",
+			wrap:    func(book *Book) BookComponent { return NewRestrictedBookDecorator(book, "staff only") },
+			wantErr: "restricted",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ebook := &Book{Title: "Go Programming Language", ISBN: "9780134190440", Copies: 2}
+			license := NewDigitalLicenseBookDecorator(test.wrap(ebook), 2, 0, time.Time{})
+			err := license.Borrow()
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("got %v, want %q", err, test.wantErr)
+				}
+				if license.GetActiveSeats() != 0 || license.GetCheckouts() != 0 {
+					t.Errorf("refused borrow took a seat: %d active, %d checkouts", license.GetActiveSeats(), license.GetCheckouts())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ebook.AvailableCopies() != 1 {
+				t.Errorf("wrapped book has %d available copies, want 1", ebook.AvailableCopies())
+			}
+			if err := license.Return(); err != nil {
+				t.Fatal(err)
+			}
+			if ebook.AvailableCopies() != 2 {
+				t.Errorf("wrapped book has %d available copies after return, want 2", ebook.AvailableCopies())
+			}
+		})
+	}
+}
+
+func TestConcurrentDigitalLoansStopAtSeats(t *testing.T) {
+	const seats, readers = 3, 64
+	ebook := &Book{Title: "Go Programming Language", ISBN: "9780134190440", Copies: readers}
+	license := NewDigitalLicenseBookDecorator(ebook, seats, 0, time.Time{})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	lent := 0
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			license.CanBorrow()
+			license.GetDetails()
+			if license.Borrow() == nil {
+				mu.Lock()
+				lent++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if lent != seats {
+		t.Errorf("lent %d e-books, want %d", lent, seats)
+	}
+	if license.GetActiveSeats() != seats || license.GetCheckouts() != seats {
+		t.Errorf("license has %d active seats and %d checkouts, want %d", license.GetActiveSeats(), license.GetCheckouts(), seats)
+	}
+	if ebook.AvailableCopies() != readers-seats {
+		t.Errorf("wrapped book has %d available copies, want %d", ebook.AvailableCopies(), readers-seats)
+	}
+}
@@ -0,0 +1,76 @@
+package decorator
+
+import (
+	"fmt"
+	"time"
+
+	"library-management-system/internal/money"
+)
+
+// FeeBasis defines what a fee amount is charged for
+type FeeBasis string
+
+// Supported fee bases
+const (
+	FeePerLoan FeeBasis = "loan"
+	FeePerDay  FeeBasis = "day"
+)
+
+// FeeStacking defines how a fee combines with the fees of the decorators it wraps
+type FeeStacking string
+
+// Supported fee stacking rules
+const (
+	// FeeStackAdd charges this fee on top of the wrapped fees
+	FeeStackAdd FeeStacking = "add"
+	// FeeStackHighest keeps only the most expensive fee of the stack
+	FeeStackHighest FeeStacking = "highest"
+	// FeeStackReplace drops the wrapped fees and charges only this fee
+	FeeStackReplace FeeStacking = "replace"
+)
+
+// Fee is a rental charge attached to a book, Amount is in minor currency units
+type Fee struct {
+	Name     string
+	Basis    FeeBasis
+	Amount   int64
+	Stacking FeeStacking
+}
+
+// ChargeFor returns the amount charged for one loan under the given loan rules
+// Per-day fees are charged for every started day of the loan period
+func (f Fee) ChargeFor(rules LoanRules) int64 {
+	if f.Basis != FeePerDay {
+		return f.Amount
+	}
+	day := 24 * time.Hour
+	days := int64((rules.Period + day - 1) / day)
+	if days < 1 {
+		days = 1
+	}
+	return f.Amount * days
+}
+
+// String returns a human readable description of the fee
+func (f Fee) String() string {
+	return fmt.Sprintf("%s %s per %s", f.Name, money.Format(f.Amount), f.Basis)
+}
+
+// combineFees applies the stacking rule of fee to the fees of the wrapped stack
+func combineFees(wrapped []Fee, fee Fee, rules LoanRules) []Fee {
+	switch fee.Stacking {
+	case FeeStackReplace:
+		return []Fee{fee}
+	case FeeStackHighest:
+		highest := fee
+		for _, other := range wrapped {
+			if other.ChargeFor(rules) > highest.ChargeFor(rules) {
+				highest = other
+			}
+		}
+		return []Fee{highest}
+	}
+	fees := make([]Fee, 0, len(wrapped)+1)
+	fees = append(fees, wrapped...)
+	return append(fees, fee)
+}
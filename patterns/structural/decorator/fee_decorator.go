@@ -0,0 +1,79 @@
+package decorator
+
+import (
+	"fmt"
+	"time"
+)
+
+// FeeBookDecorator wraps a high-demand book that carries a rental fee
+// The fee decorator Borrow is called on posts the combined fees of the stack, the ones it wraps
+// only borrow, so the same decorator can be used on its own and inside other stacks
+// Embeds BaseBookDecorator for default delegation, overrides only changed behavior
+type FeeBookDecorator struct {
+	BaseBookDecorator
+	fee     Fee
+	account *PatronAccount
+}
+
+// NewFeeBookDecorator creates a new fee decorator posting charges to the given account
+func NewFeeBookDecorator(book BookComponent, fee Fee, account *PatronAccount) *FeeBookDecorator {
+	if fee.Stacking == "" {
+		fee.Stacking = FeeStackAdd
+	}
+	return &FeeBookDecorator{
+		BaseBookDecorator: BaseBookDecorator{Component: book},
+		fee:               fee,
+		account:           account,
+	}
+}
+
+// GetDetails returns the book details with fee info
+func (fd *FeeBookDecorator) GetDetails() string {
+	return fd.details(fd.Component.GetDetails())
+}
+
+// detailsWithoutLoan describes the book like GetDetails for an outer loan period decorator
+func (fd *FeeBookDecorator) detailsWithoutLoan() string {
+	return fd.details(detailsWithoutLoan(fd.Component))
+}
+
+// details appends the fee info to the details of the wrapped book
+func (fd *FeeBookDecorator) details(inner string) string {
+	return fmt.Sprintf("%s [Fee: %s]", inner, fd.fee)
+}
+
+// GetFees returns the wrapped fees combined with this fee by its stacking rule
+func (fd *FeeBookDecorator) GetFees() []Fee {
+	return combineFees(fd.Component.GetFees(), fd.fee, fd.GetLoanRules())
+}
+
+// CanBorrow returns false without a patron account, the fees could not be charged
+func (fd *FeeBookDecorator) CanBorrow() bool {
+	return fd.account != nil && fd.Component.CanBorrow()
+}
+
+// Borrow borrows the wrapped book and posts the fees to the patron account
+// Fee decorators inside the stack only borrow, this one posts the fees combined by their stacking rules
+func (fd *FeeBookDecorator) Borrow() error {
+	if fd.account == nil {
+		return fmt.Errorf("cannot borrow a fee-charging book without a patron account")
+	}
+	if err := borrowUncharged(fd.Component); err != nil {
+		return err
+	}
+	rules := fd.GetLoanRules()
+	for _, fee := range fd.GetFees() {
+		fd.account.Post(Charge{
+			ISBN:        fd.GetISBN(),
+			Description: fee.Name,
+			Amount:      fee.ChargeFor(rules),
+			PostedAt:    time.Now(),
+		})
+	}
+	return nil
+}
+
+// borrowUncharged borrows the wrapped book for an outer fee decorator, which posts the fees
+func (fd *FeeBookDecorator) borrowUncharged() error {
+	return borrowUncharged(fd.Component)
+}
@@ -0,0 +1,106 @@
+package decorator
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowBook is a book whose loans take a moment, so concurrent borrows overlap
+type slowBook struct {
+	*Book
+}
+
+// Borrow waits before lending a copy
+func (sb slowBook) Borrow() error {
+	time.Sleep(time.Millisecond)
+	return sb.Book.Borrow()
+}
+
+func TestFeeStacking(t *testing.T) {
+	rental := Fee{Name: "Rental", Basis: FeePerLoan, Amount: 500}
+	tests := []struct {
+		name    string
+		outer   Fee
+		want    []int64
+		balance int64
+	}{
+		{name: "add", outer: Fee{Name: "High demand", Basis: FeePerLoan, Amount: 300, Stacking: FeeStackAdd}, want: []int64{500, 300}, balance: 800},
+		{name: "highest keeps wrapped", outer: Fee{Name: "High demand", Basis: FeePerLoan, Amount: 300, Stacking: FeeStackHighest}, want: []int64{500}, balance: 500},
+		{name: "highest keeps outer", outer: Fee{Name: "Weekly", Basis: FeePerDay, Amount: 100, Stacking: FeeStackHighest}, want: []int64{700}, balance: 700},
+		{name: "replace", outer: Fee{Name: "High demand", Basis: FeePerLoan, Amount: 300, Stacking: FeeStackReplace}, want: []int64{300}, balance: 300},
+		{name: "default is add", outer: Fee{Name: "High demand", Basis: FeePerLoan, Amount: 300}, want: []int64{500, 300}, balance: 800},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			account := NewPatronAccount("student-123")
+			book := &Book{Title: "Inception", ISBN: "0883929121625", Copies: 1}
+			weekly := NewDailyLoanBookDecorator(book, 7, 0)
+			stack := NewFeeBookDecorator(NewFeeBookDecorator(weekly, rental, account), test.outer, account)
+
+			if err := stack.Borrow(); err != nil {
+				t.Fatal(err)
+			}
+			charges := account.GetCharges()
+			if len(charges) != len(test.want) {
+				t.Fatalf("%d charges posted, want %d", len(charges), len(test.want))
+			}
+			for i, charge := range charges {
+				if charge.Amount != test.want[i] {
+					t.Errorf("charge %d = %d, want %d", i, charge.Amount, test.want[i])
+				}
+			}
+			if balance := account.Balance(); balance != test.balance {
+				t.Errorf("balance = %d, want %d", balance, test.balance)
+			}
+		})
+	}
+}
+
+func TestFeeWithoutAccountCannotBorrow(t *testing.T) {
+	book := &Book{Title: "Inception", ISBN: "0883929121625", Copies: 1}
+	fee := NewFeeBookDecorator(book, Fee{Name: "Rental", Basis: FeePerLoan, Amount: 500}, nil)
+	if fee.CanBorrow() {
+		t.Error("CanBorrow is true without an account")
+	}
+	if err := fee.Borrow(); err == nil {
+		t.Error("borrowed without an account")
+	}
+	if loaned := book.LoanedCopies(); loaned != 0 {
+		t.Errorf("loaned = %d, want 0", loaned)
+	}
+}
+
+func TestSharedInnerFeeChargesWhenBorrowedDirectly(t *testing.T) {
+	const loans = 50
+	book := slowBook{&Book{Title: "Inception", ISBN: "0883929121625", Copies: 2 * loans}}
+	direct := NewPatronAccount("staff-7")
+	stacked := NewPatronAccount("student-123")
+	inner := NewFeeBookDecorator(book, Fee{Name: "Rental", Basis: FeePerLoan, Amount: 500}, direct)
+	outer := NewFeeBookDecorator(inner, Fee{Name: "High demand", Basis: FeePerLoan, Amount: 300}, stacked)
+
+	var wg sync.WaitGroup
+	for range loans {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := outer.Borrow(); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := inner.Borrow(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if balance := direct.Balance(); balance != loans*500 {
+		t.Errorf("direct balance = %d, want %d", balance, loans*500)
+	}
+	if balance := stacked.Balance(); balance != loans*800 {
+		t.Errorf("stacked balance = %d, want %d", balance, loans*800)
+	}
+}
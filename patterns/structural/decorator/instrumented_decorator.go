@@ -0,0 +1,82 @@
+package decorator
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// InstrumentedBookDecorator records counts and latencies of circulation operations
+// and emits a structured log event for each of them
+// Embeds BaseBookDecorator for default delegation, overrides only changed behavior
+type InstrumentedBookDecorator struct {
+	BaseBookDecorator
+	metrics *Metrics
+	logger  *slog.Logger
+}
+
+// NewInstrumentedBookDecorator creates a new instrumentation decorator
+// A nil logger falls back to slog.Default()
+func NewInstrumentedBookDecorator(book BookComponent, metrics *Metrics, logger *slog.Logger) *InstrumentedBookDecorator {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &InstrumentedBookDecorator{
+		BaseBookDecorator: BaseBookDecorator{Component: book},
+		metrics:           metrics,
+		logger:            logger,
+	}
+}
+
+// CanBorrow delegates to the wrapped component and records the check
+func (ibd *InstrumentedBookDecorator) CanBorrow() bool {
+	start := time.Now()
+	allowed := ibd.Component.CanBorrow()
+	ibd.record(OperationCanBorrow, time.Since(start), !allowed, nil)
+	return allowed
+}
+
+// Borrow delegates to the wrapped component and records the outcome
+func (ibd *InstrumentedBookDecorator) Borrow() error {
+	return ibd.borrow(ibd.Component.Borrow)
+}
+
+// borrowUncharged records the borrow of an outer fee decorator
+func (ibd *InstrumentedBookDecorator) borrowUncharged() error {
+	return ibd.borrow(ibd.BaseBookDecorator.borrowUncharged)
+}
+
+// borrow runs the borrow of the wrapped component and records the outcome
+func (ibd *InstrumentedBookDecorator) borrow(next func() error) error {
+	start := time.Now()
+	err := next()
+	ibd.record(OperationBorrow, time.Since(start), err != nil, err)
+	return err
+}
+
+// Return delegates to the wrapped component and records the outcome
+func (ibd *InstrumentedBookDecorator) Return() error {
+	start := time.Now()
+	err := ibd.Component.Return()
+	ibd.record(OperationReturn, time.Since(start), err != nil, err)
+	return err
+}
+
+// record updates the metrics and logs the operation
+func (ibd *InstrumentedBookDecorator) record(operation string, latency time.Duration, failed bool, err error) {
+	isbn := ibd.GetISBN()
+	ibd.metrics.Record(isbn, operation, latency, failed)
+
+	level := slog.LevelInfo
+	attrs := []slog.Attr{
+		slog.String("isbn", isbn),
+		slog.String("operation", operation),
+		slog.Duration("latency", latency),
+		slog.Bool("refused", failed),
+	}
+	if err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	ibd.logger.LogAttrs(context.Background(), level, "book operation", attrs...)
+}
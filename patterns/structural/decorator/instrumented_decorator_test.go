@@ -0,0 +1,82 @@
+package decorator
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestInstrumentationCounters(t *testing.T) {
+	tests := []struct {
+		name      string
+		copies    int
+		steps     []string
+		operation string
+		want      OperationStats
+	}{
+		{
+			name:      "successful borrows",
+			copies:    2,
+			steps:     []string{OperationBorrow, OperationBorrow},
+			operation: OperationBorrow,
+			want:      OperationStats{Calls: 2},
+		},
+		{
+			name:      "refused borrow",
+			copies:    1,
+			steps:     []string{OperationBorrow, OperationBorrow},
+			operation: OperationBorrow,
+			want:      OperationStats{Calls: 2, Failures: 1},
+		},
+		{
+			name:      "extra return",
+			copies:    1,
+			steps:     []string{OperationBorrow, OperationReturn, OperationReturn},
+			operation: OperationReturn,
+			want:      OperationStats{Calls: 2, Failures: 1},
+		},
+		{
+			name:      "availability checks",
+			copies:    1,
+			steps:     []string{OperationCanBorrow, OperationBorrow, OperationCanBorrow},
+			operation: OperationCanBorrow,
+			want:      OperationStats{Calls: 2, Failures: 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metrics := NewMetrics()
+			book := &Book{ISBN: "9780132350884", Copies: test.copies}
+			instrumented := NewInstrumentedBookDecorator(book, metrics, slog.New(slog.DiscardHandler))
+			for _, step := range test.steps {
+				switch step {
+				case OperationBorrow:
+					_ = instrumented.Borrow()
+				case OperationReturn:
+					_ = instrumented.Return()
+				case OperationCanBorrow:
+					instrumented.CanBorrow()
+				}
+			}
+			got := metrics.Get(book.ISBN, test.operation)
+			if got.Calls != test.want.Calls || got.Failures != test.want.Failures {
+				t.Errorf("%s = %d calls, %d failures, want %d calls, %d failures",
+					test.operation, got.Calls, got.Failures, test.want.Calls, test.want.Failures)
+			}
+		})
+	}
+}
+
+func TestInstrumentationRecordsBorrowBehindFee(t *testing.T) {
+	metrics := NewMetrics()
+	book := &Book{ISBN: "9780132350884", Copies: 1}
+	instrumented := NewInstrumentedBookDecorator(book, metrics, slog.New(slog.DiscardHandler))
+	fee := Fee{Name: "Rental fee", Basis: FeePerLoan, Amount: 100}
+	charged := NewFeeBookDecorator(instrumented, fee, NewPatronAccount("P-1"))
+
+	if err := charged.Borrow(); err != nil {
+		t.Fatal(err)
+	}
+	if got := metrics.Get(book.ISBN, OperationBorrow); got.Calls != 1 || got.Failures != 0 {
+		t.Errorf("borrow = %d calls, %d failures, want 1 call", got.Calls, got.Failures)
+	}
+}
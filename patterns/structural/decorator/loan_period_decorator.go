@@ -0,0 +1,53 @@
+package decorator
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoanPeriodBookDecorator wraps a book with its own loan period and renewal allowance
+// Embeds BaseBookDecorator for default delegation, overrides only changed behavior
+type LoanPeriodBookDecorator struct {
+	BaseBookDecorator
+	rules LoanRules
+}
+
+// NewLoanPeriodBookDecorator creates a new loan period decorator with the given rules
+func NewLoanPeriodBookDecorator(book BookComponent, rules LoanRules) *LoanPeriodBookDecorator {
+	return &LoanPeriodBookDecorator{
+		BaseBookDecorator: BaseBookDecorator{Component: book},
+		rules:             rules,
+	}
+}
+
+// NewShortLoanBookDecorator creates a loan period decorator counted in hours (e.g. course reserves)
+func NewShortLoanBookDecorator(book BookComponent, hours, renewals int) *LoanPeriodBookDecorator {
+	return NewLoanPeriodBookDecorator(book, LoanRules{
+		Period:   time.Duration(hours) * time.Hour,
+		Renewals: renewals,
+	})
+}
+
+// NewDailyLoanBookDecorator creates a loan period decorator counted in days (e.g. new releases)
+func NewDailyLoanBookDecorator(book BookComponent, days, renewals int) *LoanPeriodBookDecorator {
+	return NewLoanPeriodBookDecorator(book, LoanRules{
+		Period:   time.Duration(days) * 24 * time.Hour,
+		Renewals: renewals,
+	})
+}
+
+// GetDetails returns the book details with the loan rules composed from the whole stack
+// Loan period decorators inside this one are left out, so the rules are printed once
+func (lpd *LoanPeriodBookDecorator) GetDetails() string {
+	return fmt.Sprintf("%s [Loan: %s]", detailsWithoutLoan(lpd.Component), lpd.GetLoanRules())
+}
+
+// detailsWithoutLoan leaves out this decorator's loan rules for an outer loan period decorator
+func (lpd *LoanPeriodBookDecorator) detailsWithoutLoan() string {
+	return detailsWithoutLoan(lpd.Component)
+}
+
+// GetLoanRules returns the wrapped rules restricted by this decorator
+func (lpd *LoanPeriodBookDecorator) GetLoanRules() LoanRules {
+	return lpd.Component.GetLoanRules().Restrict(lpd.rules)
+}
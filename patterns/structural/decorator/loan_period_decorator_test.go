@@ -0,0 +1,126 @@
+package decorator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoanRulesStacking(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap func(BookComponent) BookComponent
+		want LoanRules
+	}{
+		{
+			name: "undecorated",
+			wrap: func(book BookComponent) BookComponent { return book },
+			want: DefaultLoanRules(),
+		},
+		{
+			name: "new release",
+			wrap: func(book BookComponent) BookComponent { return NewDailyLoanBookDecorator(book, 7, 1) },
+			want: LoanRules{Period: 7 * 24 * time.Hour, Renewals: 1},
+		},
+		{
+			name: "course reserve inside new release",
+			wrap: func(book BookComponent) BookComponent {
+				return NewDailyLoanBookDecorator(NewShortLoanBookDecorator(book, 3, 0), 7, 1)
+			},
+			want: LoanRules{Period: 3 * time.Hour, Renewals: 0},
+		},
+		{
+			name: "new release inside course reserve",
+			wrap: func(book BookComponent) BookComponent {
+				return NewShortLoanBookDecorator(NewDailyLoanBookDecorator(book, 7, 1), 3, 0)
+			},
+			want: LoanRules{Period: 3 * time.Hour, Renewals: 0},
+		},
+		{
+			name: "longer period keeps the default",
+			wrap: func(book BookComponent) BookComponent { return NewDailyLoanBookDecorator(book, 30, 5) },
+			want: DefaultLoanRules(),
+		},
+		{
+			name: "shortest period and fewest renewals from different layers",
+			wrap: func(book BookComponent) BookComponent {
+				return NewDailyLoanBookDecorator(NewDailyLoanBookDecorator(book, 7, 3), 10, 1)
+			},
+			want: LoanRules{Period: 7 * 24 * time.Hour, Renewals: 1},
+		},
+	}
+	checkout := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			book := test.wrap(&Book{Title: "Calculus", ISBN: "9781285740621", Copies: 1})
+			rules := book.GetLoanRules()
+			if rules != test.want {
+				t.Errorf("rules = %s, want %s", rules, test.want)
+			}
+			if due := rules.DueDate(checkout); !due.Equal(checkout.Add(test.want.Period)) {
+				t.Errorf("due = %s, want %s", due, checkout.Add(test.want.Period))
+			}
+		})
+	}
+}
+
+func TestLoanRulesString(t *testing.T) {
+	tests := []struct {
+		rules LoanRules
+		want  string
+	}{
+		{rules: LoanRules{Period: 7 * 24 * time.Hour, Renewals: 1}, want: "7 day(s), 1 renewal(s)"},
+		{rules: LoanRules{Period: 3 * time.Hour}, want: "3h, 0 renewal(s)"},
+		{rules: LoanRules{Period: 36 * time.Hour, Renewals: 2}, want: "36h, 2 renewal(s)"},
+	}
+	for _, test := range tests {
+		if got := test.rules.String(); got != test.want {
+			t.Errorf("String() = %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestLoanDetailsPrintComposedRulesOnce(t *testing.T) {
+	const base = "Book{Title: Calculus, Author: James Stewart, ISBN: 9781285740621, Copies: 2, Available: 2}"
+	account := NewPatronAccount("P-1")
+	tests := []struct {
+		name string
+		wrap func(BookComponent) BookComponent
+		want string
+	}{
+		{
+			name: "single loan period",
+			wrap: func(book BookComponent) BookComponent { return NewDailyLoanBookDecorator(book, 7, 1) },
+			want: base + " [Loan: 7 day(s), 1 renewal(s)]",
+		},
+		{
+			name: "stacked loan periods",
+			wrap: func(book BookComponent) BookComponent {
+				return NewShortLoanBookDecorator(NewDailyLoanBookDecorator(book, 7, 1), 3, 0)
+			},
+			want: base + " [Loan: 3h, 0 renewal(s)]",
+		},
+		{
+			name: "other decorators between loan periods keep their info",
+			wrap: func(book BookComponent) BookComponent {
+				fee := Fee{Name: "Rental fee", Basis: FeePerLoan, Amount: 100}
+				return NewShortLoanBookDecorator(NewFeeBookDecorator(NewDailyLoanBookDecorator(book, 7, 1), fee, account), 3, 0)
+			},
+			want: base + " [Fee: Rental fee 1.00 per loan] [Loan: 3h, 0 renewal(s)]",
+		},
+		{
+			name: "decorators outside the loan period",
+			wrap: func(book BookComponent) BookComponent {
+				return NewSuppressedBookDecorator(NewDailyLoanBookDecorator(NewDailyLoanBookDecorator(book, 10, 1), 7, 3), "staff only")
+			},
+			want: base + " [Loan: 7 day(s), 1 renewal(s)] [Suppressed: staff only]",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			book := test.wrap(&Book{Title: "Calculus", Author: "James Stewart", ISBN: "9781285740621", Copies: 2})
+			if got := book.GetDetails(); got != test.want {
+				t.Errorf("details = %s\nwant      %s", got, test.want)
+			}
+		})
+	}
+}
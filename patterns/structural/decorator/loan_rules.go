@@ -0,0 +1,54 @@
+package decorator
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultLoanPeriod is the loan period of a book without loan decorators
+const DefaultLoanPeriod = 14 * 24 * time.Hour
+
+// DefaultRenewals is the renewal allowance of a book without loan decorators
+const DefaultRenewals = 2
+
+// LoanRules describes how long a book may be borrowed and how often the loan can be renewed
+type LoanRules struct {
+	Period   time.Duration
+	Renewals int
+}
+
+// DefaultLoanRules returns the loan rules applied to an undecorated book
+func DefaultLoanRules() LoanRules {
+	return LoanRules{Period: DefaultLoanPeriod, Renewals: DefaultRenewals}
+}
+
+// Restrict combines two rules, keeping the shorter period and the smaller renewal allowance
+func (lr LoanRules) Restrict(other LoanRules) LoanRules {
+	result := lr
+	if other.Period < result.Period {
+		result.Period = other.Period
+	}
+	if other.Renewals < result.Renewals {
+		result.Renewals = other.Renewals
+	}
+	return result
+}
+
+// DueDate returns the due date of a loan checked out at the given time
+func (lr LoanRules) DueDate(checkout time.Time) time.Time {
+	return checkout.Add(lr.Period)
+}
+
+// String returns a human readable description of the loan rules
+func (lr LoanRules) String() string {
+	return fmt.Sprintf("%s, %d renewal(s)", formatLoanPeriod(lr.Period), lr.Renewals)
+}
+
+// formatLoanPeriod prints whole days as days and everything else as hours
+func formatLoanPeriod(period time.Duration) string {
+	day := 24 * time.Hour
+	if period >= day && period%day == 0 {
+		return fmt.Sprintf("%d day(s)", period/day)
+	}
+	return fmt.Sprintf("%gh", period.Hours())
+}
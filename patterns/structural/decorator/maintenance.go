@@ -0,0 +1,223 @@
+package decorator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"library-management-system/internal/render"
+)
+
+// MaintenanceList keeps track of the copies currently out for repair
+// Every copy sent out gets its own repair ticket, so several copies of one title can be out at once;
+// a list loaded from a file writes every change back to it
+type MaintenanceList struct {
+	items      []*UnderRepairBookDecorator
+	nextTicket int
+	path       string
+}
+
+// NewMaintenanceList creates an empty maintenance list
+func NewMaintenanceList() *MaintenanceList {
+	return &MaintenanceList{}
+}
+
+// SendForRepair takes one copy of the book out of service and adds it to the list under a new ticket
+func (ml *MaintenanceList) SendForRepair(book BookComponent, damage, vendor string, sentAt, expectedReturn time.Time) (*UnderRepairBookDecorator, error) {
+	item, err := ml.add(book, "", damage, vendor, sentAt, expectedReturn)
+	if err != nil {
+		return nil, err
+	}
+	if err := ml.save(); err != nil {
+		ml.remove(item)
+		_ = ml.restore(item)
+		return nil, err
+	}
+	return item, nil
+}
+
+// ReceiveFromRepair puts the copy with the given repair ticket back in service and returns the book
+func (ml *MaintenanceList) ReceiveFromRepair(ticket string) (BookComponent, error) {
+	for _, item := range ml.items {
+		if item.ticket != ticket {
+			continue
+		}
+		if err := ml.restore(item); err != nil {
+			return nil, err
+		}
+		ml.remove(item)
+		if err := ml.save(); err != nil {
+			ml.items = append(ml.items, item)
+			_ = ml.takeOut(item)
+			return nil, err
+		}
+		return item.Component, nil
+	}
+	return nil, fmt.Errorf("no copy under repair with ticket '%s'", ticket)
+}
+
+// add validates a repair, takes the copy out of service and records it under the ticket, a new one if empty
+func (ml *MaintenanceList) add(book BookComponent, ticket, damage, vendor string, sentAt, expectedReturn time.Time) (*UnderRepairBookDecorator, error) {
+	if sentAt.IsZero() || expectedReturn.IsZero() {
+		return nil, fmt.Errorf("a repair of '%s' requires when it was sent and when it is expected back", book.GetTitle())
+	}
+	if !expectedReturn.After(sentAt) {
+		return nil, fmt.Errorf("repair of '%s' is expected back %s, before it was sent %s",
+			book.GetTitle(), expectedReturn.Format(time.DateOnly), sentAt.Format(time.DateOnly))
+	}
+	for _, existing := range ml.items {
+		if ticket != "" && existing.ticket == ticket {
+			return nil, fmt.Errorf("repair ticket '%s' is used twice", ticket)
+		}
+	}
+	item := newUnderRepairBookDecorator(book, damage, vendor, sentAt, expectedReturn)
+	if err := ml.takeOut(item); err != nil {
+		return nil, err
+	}
+	var number int
+	if _, err := fmt.Sscanf(ticket, "RP-%d", &number); err == nil && number > ml.nextTicket {
+		ml.nextTicket = number
+	}
+	if ticket == "" {
+		ml.nextTicket++
+		ticket = fmt.Sprintf("RP-%d", ml.nextTicket)
+	}
+	item.ticket = ticket
+	ml.items = append(ml.items, item)
+	return item, nil
+}
+
+// takeOut removes the repaired copy from the copies of its base book
+func (ml *MaintenanceList) takeOut(item *UnderRepairBookDecorator) error {
+	base, ok := baseBook(item.Component)
+	if !ok {
+		return fmt.Errorf("cannot find the copies of '%s'", item.GetTitle())
+	}
+	return base.SendForRepair()
+}
+
+// restore puts the repaired copy back among the copies of its base book
+func (ml *MaintenanceList) restore(item *UnderRepairBookDecorator) error {
+	base, ok := baseBook(item.Component)
+	if !ok {
+		return fmt.Errorf("cannot find the copies of '%s'", item.GetTitle())
+	}
+	return base.ReceiveFromRepair()
+}
+
+// remove drops the item from the list
+func (ml *MaintenanceList) remove(item *UnderRepairBookDecorator) {
+	for i := range ml.items {
+		if ml.items[i] == item {
+			ml.items = append(ml.items[:i], ml.items[i+1:]...)
+			return
+		}
+	}
+}
+
+// RepairRecord is one copy out for repair as stored in a maintenance file
+type RepairRecord struct {
+	Ticket         string    `json:"ticket,omitempty"`
+	Title          string    `json:"title"`
+	Author         string    `json:"author"`
+	ISBN           string    `json:"isbn"`
+	Damage         string    `json:"damage"`
+	Vendor         string    `json:"vendor"`
+	SentAt         time.Time `json:"sent_at"`
+	ExpectedReturn time.Time `json:"expected_return"`
+}
+
+// LoadMaintenanceFile reads the copies out for repair from a JSON file and takes them out of service
+// in the repository; later changes to the list are saved back to the file
+func LoadMaintenanceFile(path string, repository *BookRepository) (*MaintenanceList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read maintenance file: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var records []RepairRecord
+	if err := decoder.Decode(&records); err != nil {
+		return nil, fmt.Errorf("invalid maintenance file: %w", err)
+	}
+	maintenance := NewMaintenanceList()
+	for i, record := range records {
+		book, exists := repository.Book(record.ISBN)
+		if !exists {
+			return nil, fmt.Errorf("repair record %d: book with ISBN '%s' not found", i+1, record.ISBN)
+		}
+		if _, err := maintenance.add(book, record.Ticket, record.Damage, record.Vendor, record.SentAt, record.ExpectedReturn); err != nil {
+			return nil, fmt.Errorf("repair record %d: %w", i+1, err)
+		}
+	}
+	maintenance.path = path
+	return maintenance, nil
+}
+
+// save writes the list back to the file it was loaded from, lists without a file are kept in memory only
+func (ml *MaintenanceList) save() error {
+	if ml.path == "" {
+		return nil
+	}
+	records := make([]RepairRecord, len(ml.items))
+	for i, item := range ml.items {
+		records[i] = RepairRecord{
+			Ticket:         item.ticket,
+			Title:          item.GetTitle(),
+			Author:         item.GetAuthor(),
+			ISBN:           item.GetISBN(),
+			Damage:         item.damage,
+			Vendor:         item.vendor,
+			SentAt:         item.sentAt,
+			ExpectedReturn: item.expectedReturn,
+		}
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot save maintenance file: %w", err)
+	}
+	if err := os.WriteFile(ml.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("cannot save maintenance file: %w", err)
+	}
+	return nil
+}
+
+// Items returns the books under repair, longest out first
+func (ml *MaintenanceList) Items() []*UnderRepairBookDecorator {
+	items := append([]*UnderRepairBookDecorator(nil), ml.items...)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].sentAt.Before(items[j].sentAt)
+	})
+	return items
+}
+
+// Display writes all copies under repair and how long they have been out with the given renderer
+func (ml *MaintenanceList) Display(w io.Writer, renderer render.Renderer, now time.Time) error {
+	items := ml.Items()
+	report := render.Report{
+		Title:   fmt.Sprintf("Maintenance list contains %d book(s):", len(items)),
+		Columns: []string{"ticket", "title", "isbn", "damage", "vendor", "days_out", "expected_back", "late"},
+		Line: func(i int, row []string) string {
+			late := ""
+			if row[7] != "" {
+				late = " LATE"
+			}
+			return fmt.Sprintf("%d. %s %s (ISBN: %s) - %s at %s, out %s day(s), expected back %s%s",
+				i+1, row[0], row[1], row[2], row[3], row[4], row[5], row[6], late)
+		},
+	}
+	for _, item := range items {
+		late := ""
+		if item.IsLate(now) {
+			late = "yes"
+		}
+		report.Rows = append(report.Rows, []string{item.ticket, item.GetTitle(), item.GetISBN(), item.damage, item.vendor,
+			strconv.Itoa(int(item.TimeOut(now).Hours() / 24)), item.expectedReturn.Format(time.DateOnly), late})
+	}
+	return renderer.Render(w, report)
+}
@@ -0,0 +1,141 @@
+package decorator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var repairSentAt = time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+
+func TestSendForRepairTakesCopyOutOfService(t *testing.T) {
+	book := &Book{Title: "The Hobbit", ISBN: "9780547928227", Copies: 2}
+	maintenance := NewMaintenanceList()
+
+	item, err := maintenance.SendForRepair(book, "water damage", "Paper Care Studio", repairSentAt, repairSentAt.AddDate(0, 0, 14))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if available := book.AvailableCopies(); available != 1 {
+		t.Errorf("available = %d, want 1", available)
+	}
+	if !item.CanBorrow() {
+		t.Error("the copy left on the shelf cannot be borrowed through the repair record")
+	}
+	if err := book.Borrow(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := maintenance.SendForRepair(book, "torn pages", "City Bindery", repairSentAt, repairSentAt.AddDate(0, 0, 14)); err == nil {
+		t.Error("a loaned copy was sent for repair")
+	}
+
+	if _, err := maintenance.ReceiveFromRepair(item.GetTicket()); err != nil {
+		t.Fatal(err)
+	}
+	if available, repairing := book.AvailableCopies(), book.RepairCopies(); available != 1 || repairing != 0 {
+		t.Errorf("after receiving: available %d, under repair %d, want 1 and 0", available, repairing)
+	}
+	if _, err := maintenance.ReceiveFromRepair(item.GetTicket()); err == nil {
+		t.Error("a ticket was received twice")
+	}
+}
+
+func TestSendForRepairValidatesDates(t *testing.T) {
+	tests := []struct {
+		name           string
+		sentAt         time.Time
+		expectedReturn time.Time
+	}{
+		{name: "no expected return", sentAt: repairSentAt},
+		{name: "no sent date", expectedReturn: repairSentAt},
+		{name: "back before sent", sentAt: repairSentAt, expectedReturn: repairSentAt.AddDate(0, 0, -1)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			book := &Book{Title: "The Hobbit", ISBN: "9780547928227", Copies: 1}
+			if _, err := NewMaintenanceList().SendForRepair(book, "water damage", "Paper Care Studio", test.sentAt, test.expectedReturn); err == nil {
+				t.Fatal("repair accepted")
+			}
+			if available := book.AvailableCopies(); available != 1 {
+				t.Errorf("available = %d after a rejected repair, want 1", available)
+			}
+		})
+	}
+}
+
+func TestMaintenanceFileIsSavedOnChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "maintenance.json")
+	records := `[{"ticket": "RP-4", "title": "The Hobbit", "author": "J.R.R. Tolkien", "isbn": "9780547928227",
+		"damage": "water damage", "vendor": "Paper Care Studio",
+		"sent_at": "2025-01-20T09:00:00Z", "expected_return": "2025-02-10T17:00:00Z"}]`
+	if err := os.WriteFile(path, []byte(records), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hobbit := &Book{Title: "The Hobbit", Author: "J.R.R. Tolkien", ISBN: "9780547928227", Copies: 2}
+	repository := NewBookRepository(nil)
+	repository.AddBook(hobbit)
+
+	maintenance, err := LoadMaintenanceFile(path, repository)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if available := hobbit.AvailableCopies(); available != 1 {
+		t.Errorf("available after loading = %d, want 1", available)
+	}
+	item, err := maintenance.SendForRepair(hobbit, "loose pages", "City Bindery", repairSentAt, repairSentAt.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.GetTicket() != "RP-5" {
+		t.Errorf("ticket = %s, want RP-5 after the loaded RP-4", item.GetTicket())
+	}
+	if _, err := maintenance.ReceiveFromRepair("RP-4"); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := LoadMaintenanceFile(path, NewBookRepository(nil))
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("loading against an empty repository: got %v, want book not found", err)
+	}
+	repository = NewBookRepository(nil)
+	repository.AddBook(&Book{Title: "The Hobbit", ISBN: "9780547928227", Copies: 2})
+	if reloaded, err = LoadMaintenanceFile(path, repository); err != nil {
+		t.Fatal(err)
+	}
+	items := reloaded.Items()
+	if len(items) != 1 || items[0].GetTicket() != "RP-5" || items[0].GetDamage() != "loose pages" {
+		t.Errorf("saved list = %v, want only RP-5", items)
+	}
+}
+
+func TestLoadMaintenanceFileRejectsInvalidRecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		records string
+		want    string
+	}{
+		{name: "unknown book", records: `[{"isbn": "000", "sent_at": "2025-01-20T09:00:00Z", "expected_return": "2025-02-10T17:00:00Z"}]`, want: "not found"},
+		{name: "no expected return", records: `[{"isbn": "9780547928227", "sent_at": "2025-01-20T09:00:00Z"}]`, want: "expected back"},
+		{name: "unknown key", records: `[{"isbn": "9780547928227", "sent": "2025-01-20T09:00:00Z"}]`, want: "unknown field"},
+		{name: "more copies than owned", records: `[
+			{"isbn": "9780547928227", "sent_at": "2025-01-20T09:00:00Z", "expected_return": "2025-02-10T17:00:00Z"},
+			{"isbn": "9780547928227", "sent_at": "2025-01-20T09:00:00Z", "expected_return": "2025-02-10T17:00:00Z"}]`, want: "no copies"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "maintenance.json")
+			if err := os.WriteFile(path, []byte(test.records), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			repository := NewBookRepository(nil)
+			repository.AddBook(&Book{Title: "The Hobbit", ISBN: "9780547928227", Copies: 1})
+			_, err := LoadMaintenanceFile(path, repository)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("got %v, want an error containing %q", err, test.want)
+			}
+		})
+	}
+}
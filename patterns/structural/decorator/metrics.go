@@ -0,0 +1,107 @@
+package decorator
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Operation names recorded by the instrumentation decorator
+const (
+	OperationBorrow    = "borrow"
+	OperationReturn    = "return"
+	OperationCanBorrow = "can_borrow"
+)
+
+// OperationStats holds the counters of one operation on one book
+type OperationStats struct {
+	Calls        int64         `json:"calls"`
+	Failures     int64         `json:"failures"`
+	TotalLatency time.Duration `json:"total_latency_ns"`
+	MaxLatency   time.Duration `json:"max_latency_ns"`
+}
+
+// AverageLatency returns the mean latency of the recorded calls
+func (os OperationStats) AverageLatency() time.Duration {
+	if os.Calls == 0 {
+		return 0
+	}
+	return os.TotalLatency / time.Duration(os.Calls)
+}
+
+// BookStats holds the counters of all operations on one book
+type BookStats struct {
+	ISBN       string                    `json:"isbn"`
+	Operations map[string]OperationStats `json:"operations"`
+}
+
+// Metrics collects operation counters per ISBN, safe for concurrent use
+// Metrics implements http.Handler so it can be mounted as a metrics endpoint
+type Metrics struct {
+	mu    sync.Mutex
+	books map[string]map[string]OperationStats
+}
+
+// NewMetrics creates an empty metrics registry
+func NewMetrics() *Metrics {
+	return &Metrics{
+		books: make(map[string]map[string]OperationStats),
+	}
+}
+
+// Record adds one call of an operation on a book
+// A failure is a refused borrow or return, or a CanBorrow check that returned false
+func (m *Metrics) Record(isbn, operation string, latency time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	operations, exists := m.books[isbn]
+	if !exists {
+		operations = make(map[string]OperationStats)
+		m.books[isbn] = operations
+	}
+	stats := operations[operation]
+	stats.Calls++
+	if failed {
+		stats.Failures++
+	}
+	stats.TotalLatency += latency
+	if latency > stats.MaxLatency {
+		stats.MaxLatency = latency
+	}
+	operations[operation] = stats
+}
+
+// Get returns the counters of one operation on one book
+func (m *Metrics) Get(isbn, operation string) OperationStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.books[isbn][operation]
+}
+
+// Snapshot returns a copy of all counters sorted by ISBN
+func (m *Metrics) Snapshot() []BookStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make([]BookStats, 0, len(m.books))
+	for isbn, operations := range m.books {
+		copied := make(map[string]OperationStats, len(operations))
+		for name, stats := range operations {
+			copied[name] = stats
+		}
+		snapshot = append(snapshot, BookStats{ISBN: isbn, Operations: copied})
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].ISBN < snapshot[j].ISBN
+	})
+	return snapshot
+}
+
+// ServeHTTP writes the snapshot as JSON
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(m.Snapshot()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
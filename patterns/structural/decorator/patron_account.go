@@ -0,0 +1,54 @@
+package decorator
+
+import (
+	"sync"
+	"time"
+)
+
+// Charge is an amount posted to a patron account, Amount is in minor currency units
+type Charge struct {
+	ISBN        string
+	Description string
+	Amount      int64
+	PostedAt    time.Time
+}
+
+// PatronAccount collects the charges posted for a patron
+type PatronAccount struct {
+	PatronID string
+
+	mu      sync.Mutex
+	charges []Charge
+}
+
+// NewPatronAccount creates an empty account for the patron
+func NewPatronAccount(patronID string) *PatronAccount {
+	return &PatronAccount{PatronID: patronID}
+}
+
+// Post adds a charge to the account
+func (pa *PatronAccount) Post(charge Charge) {
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+	pa.charges = append(pa.charges, charge)
+}
+
+// GetCharges returns a copy of all posted charges
+func (pa *PatronAccount) GetCharges() []Charge {
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+	charges := make([]Charge, len(pa.charges))
+	copy(charges, pa.charges)
+	return charges
+}
+
+// Balance returns the total of all posted charges
+func (pa *PatronAccount) Balance() int64 {
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+	var total int64
+	for _, charge := range pa.charges {
+		total += charge.Amount
+	}
+	return total
+}
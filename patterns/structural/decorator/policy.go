@@ -0,0 +1,208 @@
+package decorator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Decorator types that can be used in a circulation policy file
+const (
+	DecoratorReferenceOnly = "reference_only"
+	DecoratorShortLoan     = "short_loan"
+	DecoratorDailyLoan     = "daily_loan"
+	DecoratorRestricted    = "restricted"
+	DecoratorFee           = "fee"
+	DecoratorSuppressed    = "suppressed"
+)
+
+// PolicyMatch selects the books a rule applies to
+// Empty fields match any book, all non-empty fields must match;
+// a match without any field is only accepted on rules marked global
+type PolicyMatch struct {
+	ISBN       string `json:"isbn,omitempty"`
+	Category   string `json:"category,omitempty"`
+	Tag        string `json:"tag,omitempty"`
+	Collection string `json:"collection,omitempty"`
+}
+
+// Matches checks if the book satisfies every field of the match
+func (pm PolicyMatch) Matches(book *Book) bool {
+	if pm.ISBN != "" && pm.ISBN != book.ISBN {
+		return false
+	}
+	if pm.Category != "" && !strings.EqualFold(pm.Category, book.Category) {
+		return false
+	}
+	if pm.Tag != "" && !book.HasTag(pm.Tag) {
+		return false
+	}
+	if pm.Collection != "" && !strings.EqualFold(pm.Collection, book.Collection) {
+		return false
+	}
+	return true
+}
+
+// IsEmpty checks if the match has no fields and therefore selects every book
+func (pm PolicyMatch) IsEmpty() bool {
+	return pm == PolicyMatch{}
+}
+
+// DecoratorSpec describes one decorator of a policy stack
+type DecoratorSpec struct {
+	Type     string `json:"type"`
+	Hours    int    `json:"hours,omitempty"`
+	Days     int    `json:"days,omitempty"`
+	Renewals int    `json:"renewals,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Amount   int64  `json:"amount,omitempty"`
+	Basis    string `json:"basis,omitempty"`
+	Stacking string `json:"stacking,omitempty"`
+}
+
+// Validate checks that the spec names a known decorator with usable parameters
+func (ds DecoratorSpec) Validate() error {
+	switch ds.Type {
+	case DecoratorReferenceOnly:
+		return nil
+	case DecoratorShortLoan:
+		if ds.Hours <= 0 {
+			return fmt.Errorf("%s requires hours greater than zero", ds.Type)
+		}
+		if ds.Renewals < 0 {
+			return fmt.Errorf("%s cannot have negative renewals", ds.Type)
+		}
+	case DecoratorDailyLoan:
+		if ds.Days <= 0 {
+			return fmt.Errorf("%s requires days greater than zero", ds.Type)
+		}
+		if ds.Renewals < 0 {
+			return fmt.Errorf("%s cannot have negative renewals", ds.Type)
+		}
+	case DecoratorRestricted, DecoratorSuppressed:
+		if ds.Reason == "" {
+			return fmt.Errorf("%s requires a reason", ds.Type)
+		}
+	case DecoratorFee:
+		if ds.Amount <= 0 {
+			return fmt.Errorf("%s requires an amount greater than zero", ds.Type)
+		}
+		switch FeeBasis(ds.Basis) {
+		case FeePerLoan, FeePerDay:
+		default:
+			return fmt.Errorf("%s has unknown basis '%s'", ds.Type, ds.Basis)
+		}
+		switch FeeStacking(ds.Stacking) {
+		case "", FeeStackAdd, FeeStackHighest, FeeStackReplace:
+		default:
+			return fmt.Errorf("%s has unknown stacking '%s'", ds.Type, ds.Stacking)
+		}
+	default:
+		return fmt.Errorf("unknown decorator type '%s'", ds.Type)
+	}
+	return nil
+}
+
+// Wrap decorates the book with the decorator described by the spec
+// Fee decorators post their charges to the given account
+func (ds DecoratorSpec) Wrap(book BookComponent, account *PatronAccount) BookComponent {
+	switch ds.Type {
+	case DecoratorReferenceOnly:
+		return NewReferenceOnlyBookDecorator(book)
+	case DecoratorShortLoan:
+		return NewShortLoanBookDecorator(book, ds.Hours, ds.Renewals)
+	case DecoratorDailyLoan:
+		return NewDailyLoanBookDecorator(book, ds.Days, ds.Renewals)
+	case DecoratorRestricted:
+		return NewRestrictedBookDecorator(book, ds.Reason)
+	case DecoratorSuppressed:
+		return NewSuppressedBookDecorator(book, ds.Reason)
+	case DecoratorFee:
+		name := ds.Name
+		if name == "" {
+			name = "Rental fee"
+		}
+		fee := Fee{Name: name, Basis: FeeBasis(ds.Basis), Amount: ds.Amount, Stacking: FeeStacking(ds.Stacking)}
+		return NewFeeBookDecorator(book, fee, account)
+	}
+	return book
+}
+
+// PolicyRule maps matching books to a stack of decorators, innermost first
+// Global rules apply to every book and must not have a match
+type PolicyRule struct {
+	Name       string          `json:"name"`
+	Global     bool            `json:"global,omitempty"`
+	Match      PolicyMatch     `json:"match"`
+	Decorators []DecoratorSpec `json:"decorators"`
+}
+
+// CirculationPolicy is an ordered list of rules applied to every fetched book
+type CirculationPolicy struct {
+	Rules []PolicyRule `json:"rules"`
+}
+
+// LoadPolicyFile reads and validates a JSON circulation policy file
+func LoadPolicyFile(path string) (*CirculationPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	return ParsePolicy(data)
+}
+
+// ParsePolicy parses and validates a JSON circulation policy
+// Unknown keys are rejected, so a misspelled match field cannot silently turn a rule into match-all
+func ParsePolicy(data []byte) (*CirculationPolicy, error) {
+	var policy CirculationPolicy
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("invalid policy file: %w", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("invalid policy file: unexpected data after the policy")
+	}
+	for i, rule := range policy.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("policy rule %d has no name", i+1)
+		}
+		if rule.Match.IsEmpty() && !rule.Global {
+			return nil, fmt.Errorf("policy rule '%s' has an empty match, set \"global\": true to apply it to every book", rule.Name)
+		}
+		if !rule.Match.IsEmpty() && rule.Global {
+			return nil, fmt.Errorf("policy rule '%s' is global but also has a match", rule.Name)
+		}
+		for _, spec := range rule.Decorators {
+			if err := spec.Validate(); err != nil {
+				return nil, fmt.Errorf("policy rule '%s': %w", rule.Name, err)
+			}
+		}
+	}
+	return &policy, nil
+}
+
+// MatchingRules returns the rules that apply to the book, in file order
+func (cp *CirculationPolicy) MatchingRules(book *Book) []PolicyRule {
+	rules := make([]PolicyRule, 0)
+	for _, rule := range cp.Rules {
+		if rule.Match.Matches(book) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Apply wraps the book in the decorators of every matching rule
+func (cp *CirculationPolicy) Apply(book *Book, account *PatronAccount) BookComponent {
+	var component BookComponent = book
+	for _, rule := range cp.MatchingRules(book) {
+		for _, spec := range rule.Decorators {
+			component = spec.Wrap(component, account)
+		}
+	}
+	return component
+}
@@ -0,0 +1,155 @@
+package decorator
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePolicyRejectsInvalidRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{
+			name:    "misspelled match field",
+			policy:  `{"rules": [{"name": "dvd", "match": {"categroy": "DVD"}, "decorators": [{"type": "reference_only"}]}]}`,
+			wantErr: "unknown field",
+		},
+		{
+			name:    "empty match without global",
+			policy:  `{"rules": [{"name": "all", "match": {}, "decorators": [{"type": "reference_only"}]}]}`,
+			wantErr: "has an empty match",
+		},
+		{
+			name:    "global with a match",
+			policy:  `{"rules": [{"name": "all", "global": true, "match": {"tag": "x"}, "decorators": [{"type": "reference_only"}]}]}`,
+			wantErr: "is global but also has a match",
+		},
+		{
+			name:    "rule without a name",
+			policy:  `{"rules": [{"match": {"tag": "x"}, "decorators": []}]}`,
+			wantErr: "has no name",
+		},
+		{
+			name:    "unknown decorator",
+			policy:  `{"rules": [{"name": "x", "match": {"tag": "x"}, "decorators": [{"type": "laminated"}]}]}`,
+			wantErr: "unknown decorator type 'laminated'",
+		},
+		{
+			name:    "short loan without hours",
+			policy:  `{"rules": [{"name": "x", "match": {"tag": "x"}, "decorators": [{"type": "short_loan"}]}]}`,
+			wantErr: "requires hours greater than zero",
+		},
+		{
+			name:    "daily loan without days",
+			policy:  `{"rules": [{"name": "x", "match": {"tag": "x"}, "decorators": [{"type": "daily_loan", "days": 0}]}]}`,
+			wantErr: "requires days greater than zero",
+		},
+		{
+			name:    "short loan with negative renewals",
+			policy:  `{"rules": [{"name": "x", "match": {"tag": "x"}, "decorators": [{"type": "short_loan", "hours": 3, "renewals": -1}]}]}`,
+			wantErr: "short_loan cannot have negative renewals",
+		},
+		{
+			name:    "daily loan with negative renewals",
+			policy:  `{"rules": [{"name": "x", "match": {"tag": "x"}, "decorators": [{"type": "daily_loan", "days": 7, "renewals": -2}]}]}`,
+			wantErr: "daily_loan cannot have negative renewals",
+		},
+		{
+			name:    "restricted without reason",
+			policy:  `{"rules": [{"name": "x", "match": {"tag": "x"}, "decorators": [{"type": "restricted"}]}]}`,
+			wantErr: "requires a reason",
+		},
+		{
+			name:    "fee with unknown basis",
+			policy:  `{"rules": [{"name": "x", "match": {"tag": "x"}, "decorators": [{"type": "fee", "amount": 100, "basis": "week"}]}]}`,
+			wantErr: "unknown basis 'week'",
+		},
+		{
+			name:    "fee with unknown stacking",
+			policy:  `{"rules": [{"name": "x", "match": {"tag": "x"}, "decorators": [{"type": "fee", "amount": 100, "basis": "loan", "stacking": "sum"}]}]}`,
+			wantErr: "unknown stacking 'sum'",
+		},
+		{
+			name:    "trailing data",
+			policy:  `{"rules": []} {"rules": []}`,
+			wantErr: "unexpected data after the policy",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(test.policy))
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("got %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicyMatchingAndApply(t *testing.T) {
+	policy, err := LoadPolicyFile("../../../config/circulation_policy.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name          string
+		book          *Book
+		wantRules     []string
+		wantBorrow    bool
+		wantRead      bool
+		wantLoanRules LoanRules
+	}{
+		{
+			name:          "no matching rule",
+			book:          &Book{ISBN: "1", Category: "Fiction", Copies: 1},
+			wantBorrow:    true,
+			wantRead:      true,
+			wantLoanRules: DefaultLoanRules(),
+		},
+		{
+			name:          "reference collection, case insensitive",
+			book:          &Book{ISBN: "2", Collection: "reference", Copies: 1},
+			wantRules:     []string{"reference-collection"},
+			wantRead:      true,
+			wantLoanRules: DefaultLoanRules(),
+		},
+		{
+			name:          "new release on course reserve",
+			book:          &Book{ISBN: "3", Collection: "Course Reserve", Tags: []string{"New Release"}, Copies: 1},
+			wantRules:     []string{"new-releases", "course-reserves"},
+			wantBorrow:    true,
+			wantRead:      true,
+			wantLoanRules: LoanRules{Period: 3 * time.Hour},
+		},
+		{
+			name:          "rare book",
+			book:          &Book{ISBN: "4", Category: "Rare", Copies: 1},
+			wantRules:     []string{"rare-books"},
+			wantRead:      true,
+			wantLoanRules: DefaultLoanRules(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var names []string
+			for _, rule := range policy.MatchingRules(test.book) {
+				names = append(names, rule.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(test.wantRules, ",") {
+				t.Errorf("rules = %v, want %v", names, test.wantRules)
+			}
+			component := policy.Apply(test.book, NewPatronAccount("P-1"))
+			if got := component.CanBorrow(); got != test.wantBorrow {
+				t.Errorf("CanBorrow = %v, want %v", got, test.wantBorrow)
+			}
+			if got := component.CanReadInLibrary(); got != test.wantRead {
+				t.Errorf("CanReadInLibrary = %v, want %v", got, test.wantRead)
+			}
+			if got := component.GetLoanRules(); got != test.wantLoanRules {
+				t.Errorf("GetLoanRules = %s, want %s", got, test.wantLoanRules)
+			}
+		})
+	}
+}
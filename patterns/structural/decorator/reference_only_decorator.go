@@ -17,7 +17,17 @@ func NewReferenceOnlyBookDecorator(book BookComponent) *ReferenceOnlyBookDecorat
 
 // GetDetails returns the book details with reference only info
 func (robd *ReferenceOnlyBookDecorator) GetDetails() string {
-	return fmt.Sprintf("%s [Reference Only]", robd.Component.GetDetails())
+	return robd.details(robd.Component.GetDetails())
+}
+
+// detailsWithoutLoan describes the book like GetDetails for an outer loan period decorator
+func (robd *ReferenceOnlyBookDecorator) detailsWithoutLoan() string {
+	return robd.details(detailsWithoutLoan(robd.Component))
+}
+
+// details appends the reference only info to the details of the wrapped book
+func (robd *ReferenceOnlyBookDecorator) details(inner string) string {
+	return fmt.Sprintf("%s [Reference Only]", inner)
 }
 
 // CanBorrow returns false for reference only books
@@ -31,11 +41,16 @@ func (robd *ReferenceOnlyBookDecorator) CanReadInLibrary() bool {
 }
 
 // Borrow is not allowed for reference only books
-func (robd *ReferenceOnlyBookDecorator) Borrow() {
-	fmt.Println("Cannot borrow a reference only book")
+func (robd *ReferenceOnlyBookDecorator) Borrow() error {
+	return fmt.Errorf("cannot borrow a reference only book")
+}
+
+// borrowUncharged refuses like Borrow
+func (robd *ReferenceOnlyBookDecorator) borrowUncharged() error {
+	return robd.Borrow()
 }
 
 // Return is not allowed for reference only books
-func (robd *ReferenceOnlyBookDecorator) Return() {
-	fmt.Println("Cannot return a reference only book")
+func (robd *ReferenceOnlyBookDecorator) Return() error {
+	return fmt.Errorf("cannot return a reference only book")
 }
@@ -19,7 +19,17 @@ func NewReservedBookDecorator(book BookComponent, reservedBy string) *ReservedBo
 
 // GetDetails returns the book details with reservation info
 func (rbd *ReservedBookDecorator) GetDetails() string {
-	return fmt.Sprintf("%s [Reserved by: %s]", rbd.Component.GetDetails(), rbd.reservedBy)
+	return rbd.details(rbd.Component.GetDetails())
+}
+
+// detailsWithoutLoan describes the book like GetDetails for an outer loan period decorator
+func (rbd *ReservedBookDecorator) detailsWithoutLoan() string {
+	return rbd.details(detailsWithoutLoan(rbd.Component))
+}
+
+// details appends the reservation info to the details of the wrapped book
+func (rbd *ReservedBookDecorator) details(inner string) string {
+	return fmt.Sprintf("%s [Reserved by: %s]", inner, rbd.reservedBy)
 }
 
 // CanBorrow returns false because a reserved book cannot be borrowed by others
@@ -28,13 +38,18 @@ func (rbd *ReservedBookDecorator) CanBorrow() bool {
 }
 
 // Borrow is not allowed for reserved books
-func (rbd *ReservedBookDecorator) Borrow() {
-	fmt.Println("Cannot borrow a reserved book")
+func (rbd *ReservedBookDecorator) Borrow() error {
+	return fmt.Errorf("cannot borrow a reserved book")
+}
+
+// borrowUncharged refuses like Borrow
+func (rbd *ReservedBookDecorator) borrowUncharged() error {
+	return rbd.Borrow()
 }
 
 // Return is not allowed for reserved books
-func (rbd *ReservedBookDecorator) Return() {
-	fmt.Println("Cannot return a reserved book")
+func (rbd *ReservedBookDecorator) Return() error {
+	return fmt.Errorf("cannot return a reserved book")
 }
 
 // GetReservedBy returns who reserved the book
@@ -0,0 +1,58 @@
+package decorator
+
+import "fmt"
+
+// RestrictedBookDecorator wraps a book that may only be consulted under staff supervision
+// Embeds BaseBookDecorator for default delegation, overrides only changed behavior
+type RestrictedBookDecorator struct {
+	BaseBookDecorator
+	reason string
+}
+
+// NewRestrictedBookDecorator creates a new restricted book decorator
+func NewRestrictedBookDecorator(book BookComponent, reason string) *RestrictedBookDecorator {
+	return &RestrictedBookDecorator{
+		BaseBookDecorator: BaseBookDecorator{Component: book},
+		reason:            reason,
+	}
+}
+
+// GetDetails returns the book details with restriction info
+func (rd *RestrictedBookDecorator) GetDetails() string {
+	return rd.details(rd.Component.GetDetails())
+}
+
+// detailsWithoutLoan describes the book like GetDetails for an outer loan period decorator
+func (rd *RestrictedBookDecorator) detailsWithoutLoan() string {
+	return rd.details(detailsWithoutLoan(rd.Component))
+}
+
+// details appends the restriction info to the details of the wrapped book
+func (rd *RestrictedBookDecorator) details(inner string) string {
+	return fmt.Sprintf("%s [Restricted: %s]", inner, rd.reason)
+}
+
+// CanBorrow returns false for restricted books
+func (rd *RestrictedBookDecorator) CanBorrow() bool {
+	return false
+}
+
+// Borrow is not allowed for restricted books
+func (rd *RestrictedBookDecorator) Borrow() error {
+	return fmt.Errorf("cannot borrow a restricted book")
+}
+
+// borrowUncharged refuses like Borrow
+func (rd *RestrictedBookDecorator) borrowUncharged() error {
+	return rd.Borrow()
+}
+
+// Return is not allowed for restricted books
+func (rd *RestrictedBookDecorator) Return() error {
+	return fmt.Errorf("cannot return a restricted book")
+}
+
+// GetReason returns why the book is restricted
+func (rd *RestrictedBookDecorator) GetReason() string {
+	return rd.reason
+}
@@ -0,0 +1,49 @@
+package decorator
+
+import "fmt"
+
+// SuppressedBookDecorator wraps a book that must be hidden from the public catalog (OPAC)
+// e.g. withdrawn-pending or staff-only materials
+// Embeds BaseBookDecorator for default delegation, overrides only changed behavior
+type SuppressedBookDecorator struct {
+	BaseBookDecorator
+	reason string
+}
+
+// NewSuppressedBookDecorator creates a new suppressed book decorator
+func NewSuppressedBookDecorator(book BookComponent, reason string) *SuppressedBookDecorator {
+	return &SuppressedBookDecorator{
+		BaseBookDecorator: BaseBookDecorator{Component: book},
+		reason:            reason,
+	}
+}
+
+// GetDetails returns the book details with suppression info
+func (sd *SuppressedBookDecorator) GetDetails() string {
+	return sd.details(sd.Component.GetDetails())
+}
+
+// detailsWithoutLoan describes the book like GetDetails for an outer loan period decorator
+func (sd *SuppressedBookDecorator) detailsWithoutLoan() string {
+	return sd.details(detailsWithoutLoan(sd.Component))
+}
+
+// details appends the suppression info to the details of the wrapped book
+func (sd *SuppressedBookDecorator) details(inner string) string {
+	return fmt.Sprintf("%s [Suppressed: %s]", inner, sd.reason)
+}
+
+// GetReason returns why the book is hidden from the public catalog
+func (sd *SuppressedBookDecorator) GetReason() string {
+	return sd.reason
+}
+
+// Suppression reports whether any decorator in the stack suppresses the book, and why
+func Suppression(book BookComponent) (string, bool) {
+	for ; book != nil; book = unwrapComponent(book) {
+		if suppressed, ok := book.(*SuppressedBookDecorator); ok {
+			return suppressed.reason, true
+		}
+	}
+	return "", false
+}
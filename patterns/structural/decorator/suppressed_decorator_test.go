@@ -0,0 +1,79 @@
+package decorator
+
+import (
+	"testing"
+
+	"library-management-system/patterns/behavioral/strategy"
+)
+
+func TestSuppressionThroughStack(t *testing.T) {
+	tests := []struct {
+		name       string
+		wrap       func(BookComponent) BookComponent
+		wantReason string
+		wantHidden bool
+	}{
+		{
+			name: "undecorated",
+			wrap: func(book BookComponent) BookComponent { return book },
+		},
+		{
+			name: "other decorators only",
+			wrap: func(book BookComponent) BookComponent {
+				return NewReferenceOnlyBookDecorator(NewDailyLoanBookDecorator(book, 7, 1))
+			},
+		},
+		{
+			name:       "outermost",
+			wrap:       func(book BookComponent) BookComponent { return NewSuppressedBookDecorator(book, "withdrawn") },
+			wantReason: "withdrawn",
+			wantHidden: true,
+		},
+		{
+			name: "under other decorators",
+			wrap: func(book BookComponent) BookComponent {
+				return NewReferenceOnlyBookDecorator(NewSuppressedBookDecorator(NewDailyLoanBookDecorator(book, 7, 1), "staff only"))
+			},
+			wantReason: "staff only",
+			wantHidden: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			book := test.wrap(&Book{ISBN: "9780201633610", Copies: 1})
+			reason, hidden := Suppression(book)
+			if reason != test.wantReason || hidden != test.wantHidden {
+				t.Errorf("Suppression = (%q, %v), want (%q, %v)", reason, hidden, test.wantReason, test.wantHidden)
+			}
+		})
+	}
+}
+
+func TestCatalogRecordHidesSuppressedBooks(t *testing.T) {
+	visible := &Book{Title: "Clean Code", Author: "Robert C. Martin", ISBN: "9780132350884", Copies: 1}
+	staffOnly := NewReferenceOnlyBookDecorator(NewSuppressedBookDecorator(&Book{
+		Title: "Clean Code Instructor Solutions", Author: "Robert C. Martin", ISBN: "9780132350891", Copies: 1,
+	}, "staff only"))
+
+	record := CatalogRecord(staffOnly, "Technology")
+	if !record.Suppressed || record.SuppressionReason != "staff only" || record.Category != "Technology" {
+		t.Fatalf("record = %+v, want suppressed for staff only in Technology", record)
+	}
+
+	catalog := strategy.NewCatalog(nil)
+	catalog.AddBook(CatalogRecord(visible, "Technology"))
+	catalog.AddBook(record)
+	tests := []struct {
+		mode strategy.SearchMode
+		want int
+	}{
+		{mode: strategy.PublicSearch, want: 1},
+		{mode: strategy.StaffSearch, want: 2},
+	}
+	for _, test := range tests {
+		catalog.SetMode(test.mode)
+		if got := len(catalog.Find("Clean Code")); got != test.want {
+			t.Errorf("%s search found %d book(s), want %d", test.mode, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package decorator
+
+import (
+	"fmt"
+	"time"
+)
+
+// UnderRepairBookDecorator records one copy of a book sent out for repair (e.g. to the bindery)
+// The copy itself is out of service in the base book's counts, so the title stays borrowable
+// while other copies are on the shelf; create it with MaintenanceList.SendForRepair
+// Embeds BaseBookDecorator for default delegation, overrides only changed behavior
+type UnderRepairBookDecorator struct {
+	BaseBookDecorator
+	ticket         string
+	damage         string
+	vendor         string
+	sentAt         time.Time
+	expectedReturn time.Time
+}
+
+// newUnderRepairBookDecorator creates a new under repair book decorator
+func newUnderRepairBookDecorator(book BookComponent, damage, vendor string, sentAt, expectedReturn time.Time) *UnderRepairBookDecorator {
+	return &UnderRepairBookDecorator{
+		BaseBookDecorator: BaseBookDecorator{Component: book},
+		damage:            damage,
+		vendor:            vendor,
+		sentAt:            sentAt,
+		expectedReturn:    expectedReturn,
+	}
+}
+
+// GetDetails returns the book details with repair info
+func (urd *UnderRepairBookDecorator) GetDetails() string {
+	return urd.details(urd.Component.GetDetails())
+}
+
+// detailsWithoutLoan describes the book like GetDetails for an outer loan period decorator
+func (urd *UnderRepairBookDecorator) detailsWithoutLoan() string {
+	return urd.details(detailsWithoutLoan(urd.Component))
+}
+
+// details appends the repair info to the details of the wrapped book
+func (urd *UnderRepairBookDecorator) details(inner string) string {
+	return fmt.Sprintf("%s [Under Repair: %s at %s, expected back %s]",
+		inner, urd.damage, urd.vendor, urd.expectedReturn.Format(time.DateOnly))
+}
+
+// GetTicket returns the repair ticket assigned by the maintenance list
+func (urd *UnderRepairBookDecorator) GetTicket() string {
+	return urd.ticket
+}
+
+// GetDamage returns the recorded damage description
+func (urd *UnderRepairBookDecorator) GetDamage() string {
+	return urd.damage
+}
+
+// GetVendor returns the repair vendor
+func (urd *UnderRepairBookDecorator) GetVendor() string {
+	return urd.vendor
+}
+
+// GetSentAt returns when the book was sent for repair
+func (urd *UnderRepairBookDecorator) GetSentAt() time.Time {
+	return urd.sentAt
+}
+
+// GetExpectedReturn returns when the book is expected back from the vendor
+func (urd *UnderRepairBookDecorator) GetExpectedReturn() time.Time {
+	return urd.expectedReturn
+}
+
+// TimeOut returns how long the book has been out for repair at the given time
+func (urd *UnderRepairBookDecorator) TimeOut(now time.Time) time.Duration {
+	return now.Sub(urd.sentAt)
+}
+
+// IsLate checks if the vendor has kept the book past the expected return date
+func (urd *UnderRepairBookDecorator) IsLate(now time.Time) bool {
+	return now.After(urd.expectedReturn)
+}